@@ -0,0 +1,51 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+)
+
+type ApplicationContextAware interface {
+	SetApplicationContext(context ApplicationContext)
+}
+
+type EnvironmentAware interface {
+	SetEnvironment(environment core.Environment)
+}
+
+type PeaNameAware interface {
+	SetPeaName(peaName string)
+}
+
+type LoggerAware interface {
+	SetLogger(logger Logger)
+}
+
+type awarePeaProcessor struct {
+	ctx *BaseApplicationContext
+}
+
+func newAwarePeaProcessor(ctx *BaseApplicationContext) awarePeaProcessor {
+	return awarePeaProcessor{
+		ctx,
+	}
+}
+
+func (processor awarePeaProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	if aware, ok := pea.(PeaNameAware); ok {
+		aware.SetPeaName(peaName)
+	}
+	if aware, ok := pea.(EnvironmentAware); ok && processor.ctx.environment != nil {
+		aware.SetEnvironment(processor.ctx.environment)
+	}
+	if aware, ok := pea.(LoggerAware); ok && processor.ctx.logger != nil {
+		aware.SetLogger(processor.ctx.logger)
+	}
+	if aware, ok := pea.(ApplicationContextAware); ok {
+		aware.SetApplicationContext(processor.ctx)
+	}
+	return pea, nil
+}
+
+func (processor awarePeaProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
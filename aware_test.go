@@ -0,0 +1,57 @@
+package context
+
+import (
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testAwareComponent struct {
+	context     ApplicationContext
+	environment core.Environment
+	peaName     string
+	logger      Logger
+}
+
+func newTestAwareComponent() *testAwareComponent {
+	return &testAwareComponent{}
+}
+
+func (component *testAwareComponent) SetApplicationContext(context ApplicationContext) {
+	component.context = context
+}
+
+func (component *testAwareComponent) SetEnvironment(environment core.Environment) {
+	component.environment = environment
+}
+
+func (component *testAwareComponent) SetPeaName(peaName string) {
+	component.peaName = peaName
+}
+
+func (component *testAwareComponent) SetLogger(logger Logger) {
+	component.logger = logger
+}
+
+func TestBaseApplicationContext_AwarePeas(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	logger := NewSimpleLogger()
+	baseApplicationContext.SetLogger(logger)
+	env := core.NewStandardEnvironment()
+	baseApplicationContext.SetEnvironment(env)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("awareComponent", NewScannedPeaDefinition("awareComponent", goo.GetType(newTestAwareComponent)))
+
+	assert.Nil(t, baseApplicationContext.Configure())
+	pea, err := baseApplicationContext.GetPea("awareComponent")
+	assert.Nil(t, err)
+	component, ok := pea.(*testAwareComponent)
+	assert.True(t, ok)
+	assert.Equal(t, "awareComponent", component.peaName)
+	assert.Equal(t, env, component.environment)
+	assert.Equal(t, logger, component.logger)
+	assert.Equal(t, baseApplicationContext, component.context)
+	assert.Equal(t, ApplicationId("app-id"), component.context.GetAppId())
+}
@@ -2,11 +2,173 @@ package context
 
 import (
 	"errors"
+	"fmt"
 	"github.com/procyon-projects/goo"
 	core "github.com/procyon-projects/procyon-core"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+const maxCollectionIndex = 9999
+
+type PropertyConversionError struct {
+	propertyName string
+	value        interface{}
+	typeName     string
+	cause        error
+}
+
+func NewPropertyConversionError(propertyName string, value interface{}, typeName string, cause error) PropertyConversionError {
+	return PropertyConversionError{
+		propertyName,
+		value,
+		typeName,
+		cause,
+	}
+}
+
+func (err PropertyConversionError) GetPropertyName() string {
+	return err.propertyName
+}
+
+func (err PropertyConversionError) GetValue() interface{} {
+	return err.value
+}
+
+func (err PropertyConversionError) GetTypeName() string {
+	return err.typeName
+}
+
+func (err PropertyConversionError) GetCause() error {
+	return err.cause
+}
+
+func (err PropertyConversionError) Unwrap() error {
+	return err.cause
+}
+
+func (err PropertyConversionError) Error() string {
+	return "property '" + err.propertyName + "' cannot be converted to " + err.typeName + " : " + err.cause.Error()
+}
+
+type PropertyBindingFailure struct {
+	propertyName string
+	source       string
+	value        interface{}
+	typeName     string
+	cause        error
+}
+
+func NewPropertyBindingFailure(propertyName string, source string, value interface{}, typeName string, cause error) PropertyBindingFailure {
+	return PropertyBindingFailure{
+		propertyName,
+		source,
+		value,
+		typeName,
+		cause,
+	}
+}
+
+func (failure PropertyBindingFailure) GetPropertyName() string {
+	return failure.propertyName
+}
+
+func (failure PropertyBindingFailure) GetSource() string {
+	return failure.source
+}
+
+func (failure PropertyBindingFailure) GetValue() interface{} {
+	return failure.value
+}
+
+func (failure PropertyBindingFailure) GetTypeName() string {
+	return failure.typeName
+}
+
+func (failure PropertyBindingFailure) GetCause() error {
+	return failure.cause
+}
+
+func (failure PropertyBindingFailure) Unwrap() error {
+	return failure.cause
+}
+
+func (failure PropertyBindingFailure) Error() string {
+	if failure.source == "" {
+		return failure.cause.Error()
+	}
+	return failure.cause.Error() + " (source : " + failure.source + ")"
+}
+
+type BindError struct {
+	prefix   string
+	failures []PropertyBindingFailure
+}
+
+func NewBindError(prefix string, failures []PropertyBindingFailure) BindError {
+	return BindError{
+		prefix,
+		failures,
+	}
+}
+
+func (err BindError) GetPrefix() string {
+	return err.prefix
+}
+
+func (err BindError) GetFailures() []PropertyBindingFailure {
+	return err.failures
+}
+
+func (err BindError) Unwrap() error {
+	if len(err.failures) == 0 {
+		return nil
+	}
+	return err.failures[0]
+}
+
+func (err BindError) Error() string {
+	messages := make([]string, 0)
+	for _, failure := range err.failures {
+		messages = append(messages, failure.Error())
+	}
+	return "properties with the prefix '" + err.prefix + "' could not be bound : " + strings.Join(messages, "; ")
+}
+
+type bindOptions struct {
+	defaultValue string
+	omitEmpty    bool
+	unit         string
+	format       string
+}
+
+func (options bindOptions) forElement() bindOptions {
+	/* the elements of collections and maps are converted with the same unit and format */
+	return bindOptions{
+		unit:   options.unit,
+		format: options.format,
+	}
+}
+
+type bindingState struct {
+	properties      relaxedPropertyIndex
+	visitedTypes    map[reflect.Type]bool
+	ambiguities     map[string]bool
+	failures        []PropertyBindingFailure
+	boundProperties int
+}
+
+func newBindingState(properties relaxedPropertyIndex) *bindingState {
+	return &bindingState{
+		properties:   properties,
+		visitedTypes: make(map[reflect.Type]bool, 0),
+		ambiguities:  make(map[string]bool, 0),
+		failures:     make([]PropertyBindingFailure, 0),
+	}
+}
+
 type ConfigurationPropertiesBinder struct {
 	env                  core.Environment
 	typeConverterService core.TypeConverterService
@@ -31,77 +193,514 @@ func (binder ConfigurationPropertiesBinder) Bind(target interface{}) error {
 		if !goo.GetType(target).IsPointer() {
 			return errors.New("configuration properties cannot be bound as it is not a type of pointer")
 		}
-		return binder.bindTargetFields(prefix, target)
+		return binder.bindTargetFields(GetCanonicalPropertyName(prefix), target)
 	}
 	return nil
 }
 
 func (binder ConfigurationPropertiesBinder) bindTargetFields(prefix string, target interface{}) error {
-	targetTyp := goo.GetType(target).ToStructType()
-	exportedFields := targetTyp.GetExportedFields()
+	state := newBindingState(newRelaxedPropertyIndex(binder.env))
+	binder.bindStruct(prefix, target, state)
+	if len(state.failures) != 0 {
+		return NewBindError(prefix, state.failures)
+	}
+	return binder.validate(prefix, target)
+}
+
+func (binder ConfigurationPropertiesBinder) bindStruct(prefix string, target interface{}, state *bindingState) bool {
+	targetTyp := goo.GetType(target)
+	targetValue := reflect.ValueOf(target).Elem()
+	state.visitedTypes[targetValue.Type()] = true
+	defer delete(state.visitedTypes, targetValue.Type())
+
+	bound := false
+	exportedFields := targetTyp.ToStructType().GetExportedFields()
 	for _, field := range exportedFields {
-		var bindTag, defaultTag goo.Tag
-		var err error
-		bindTag, err = field.GetTagByName("json")
+		tag, ok := binder.getPropertyTag(field)
+		if !ok || !field.CanSet() {
+			continue
+		}
+		propertyName := binder.getFullPropertyName(prefix, tag.GetName())
+		options := bindOptions{
+			defaultValue: binder.getTagValue(field, "default"),
+			omitEmpty:    tag.HasOption("omitempty"),
+			unit:         strings.TrimSpace(binder.getTagValue(field, "unit")),
+			format:       binder.getTagValue(field, "format"),
+		}
+		fieldValue := targetValue.FieldByName(field.GetName())
+		bound = binder.bindValue(propertyName, fieldValue, options, state) || bound
+	}
+	return bound
+}
+
+func (binder ConfigurationPropertiesBinder) addFailure(state *bindingState, propertyName string, value interface{}, typ reflect.Type, cause error) {
+	sourceName := ""
+	if _, propertySource := state.properties.find(propertyName); propertySource != nil {
+		sourceName = propertySource.GetName()
+	}
+	state.failures = append(state.failures, NewPropertyBindingFailure(propertyName, sourceName, value, typ.String(), cause))
+}
+
+func (binder ConfigurationPropertiesBinder) containsProperty(state *bindingState, propertyName string) bool {
+	if matches, _ := state.properties.find(propertyName); len(matches) != 0 {
+		return true
+	}
+	return binder.env.ContainsProperty(propertyName)
+}
+
+func (binder ConfigurationPropertiesBinder) getProperty(state *bindingState, propertyName string, typ reflect.Type) (interface{}, bool) {
+	matches, propertySource := state.properties.find(propertyName)
+	if len(matches) == 1 {
+		state.boundProperties++
+		return propertySource.GetProperty(matches[0].name), true
+	} else if len(matches) > 1 {
+		/* different forms of the same property in a single source cannot be distinguished */
+		if !state.ambiguities[propertyName] {
+			state.ambiguities[propertyName] = true
+			candidates := make([]string, 0)
+			for _, match := range matches {
+				candidates = append(candidates, match.name)
+			}
+			binder.addFailure(state, propertyName, nil, typ, NewAmbiguousPropertyError(propertyName, propertySource.GetName(), candidates))
+		}
+		return nil, false
+	}
+	if binder.env.ContainsProperty(propertyName) {
+		state.boundProperties++
+		return binder.env.GetProperty(propertyName, ""), true
+	}
+	return nil, false
+}
+
+func (binder ConfigurationPropertiesBinder) resolvePlaceholders(state *bindingState, propertyName string, propertyValue interface{}, typ reflect.Type) (interface{}, bool) {
+	stringValue, ok := propertyValue.(string)
+	if !ok || !strings.Contains(stringValue, placeholderPrefix) {
+		return propertyValue, true
+	}
+	resolver := NewPlaceholderResolver(func(name string) (string, bool) {
+		return binder.lookupPlaceholder(state, name)
+	})
+	resolvedValue, err := resolver.Resolve(stringValue)
+	if err != nil {
+		binder.addFailure(state, propertyName, propertyValue, typ, err)
+		return nil, false
+	}
+	return resolvedValue, true
+}
+
+func (binder ConfigurationPropertiesBinder) lookupPlaceholder(state *bindingState, name string) (string, bool) {
+	if matches, propertySource := state.properties.find(name); len(matches) == 1 {
+		return fmt.Sprint(propertySource.GetProperty(matches[0].name)), true
+	}
+	if binder.env.ContainsProperty(name) {
+		return fmt.Sprint(binder.env.GetProperty(name, "")), true
+	}
+	return "", false
+}
+
+func (binder ConfigurationPropertiesBinder) getTagValue(field goo.Field, name string) string {
+	tag, err := field.GetTagByName(name)
+	if err != nil {
+		return ""
+	}
+	return tag.Value
+}
+
+func (binder ConfigurationPropertiesBinder) getPropertyTag(field goo.Field) (PropertyTag, bool) {
+	/* the tag property takes precedence over the tags json and yaml */
+	for _, tagName := range []string{"property", "json", "yaml"} {
+		tag, err := field.GetTagByName(tagName)
 		if err != nil {
-			bindTag, err = field.GetTagByName("yaml")
+			continue
+		}
+		propertyTag := ParsePropertyTag(tag.Value)
+		if propertyTag.GetName() == "-" {
+			return propertyTag, false
 		}
+		if propertyTag.GetName() == "" {
+			propertyTag.name = field.GetName()
+		}
+		return propertyTag, true
+	}
+	/* fields only having a default value are bound by their names */
+	if _, err := field.GetTagByName("default"); err == nil {
+		return NewPropertyTag(field.GetName()), true
+	}
+	return PropertyTag{}, false
+}
+
+func (binder ConfigurationPropertiesBinder) isNestedStructType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && !isValueType(typ)
+}
+
+func (binder ConfigurationPropertiesBinder) bindValue(propertyName string, value reflect.Value, options bindOptions, state *bindingState) bool {
+	typ := value.Type()
+	switch {
+	case isValueType(typ) || (typ.Kind() == reflect.Ptr && isValueType(typ.Elem())):
+		return binder.bindLeaf(propertyName, value, options, state)
+	case binder.isNestedStructType(typ):
+		/* self-referencing types are only recursed into again if there is any property for them */
+		if state.visitedTypes[typ] && !binder.containsPropertiesWithPrefix(state, propertyName) {
+			return false
+		}
+		return binder.bindStruct(propertyName, value.Addr().Interface(), state)
+	case typ.Kind() == reflect.Ptr && binder.isNestedStructType(typ.Elem()):
+		if state.visitedTypes[typ.Elem()] && !binder.containsPropertiesWithPrefix(state, propertyName) {
+			return false
+		}
+		if !value.IsNil() {
+			return binder.bindStruct(propertyName, value.Interface(), state)
+		}
+		/* nil pointers are only allocated if any of the nested properties is specified, defaults are not enough */
+		newInstance := reflect.New(typ.Elem())
+		boundProperties := state.boundProperties
+		if !binder.bindStruct(propertyName, newInstance.Interface(), state) || boundProperties == state.boundProperties {
+			return false
+		}
+		value.Set(newInstance)
+		return true
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		return binder.bindCollection(propertyName, value, options, state)
+	case typ.Kind() == reflect.Map:
+		return binder.bindMap(propertyName, value, options, state)
+	}
+	return binder.bindLeaf(propertyName, value, options, state)
+}
+
+func (binder ConfigurationPropertiesBinder) bindLeaf(propertyName string, value reflect.Value, options bindOptions, state *bindingState) bool {
+	propertyValue, ok := binder.getProperty(state, propertyName, value.Type())
+	/* empty values of the fields having the option omitempty are treated as if they are not specified */
+	if stringValue, isString := propertyValue.(string); ok && isString && options.omitEmpty && stringValue == "" {
+		ok = false
+	}
+	if !ok {
+		if options.defaultValue == "" || state.ambiguities[propertyName] {
+			return false
+		}
+		propertyValue = options.defaultValue
+	}
+	propertyValue, ok = binder.resolvePlaceholders(state, propertyName, propertyValue, value.Type())
+	if !ok || propertyValue == nil {
+		return false
+	}
+	convertedValue, ok := binder.convertLeafValue(propertyName, propertyValue, value.Type(), options, state)
+	if !ok {
+		return false
+	}
+	value.Set(convertedValue)
+	return true
+}
+
+func (binder ConfigurationPropertiesBinder) convertLeafValue(propertyName string, propertyValue interface{}, typ reflect.Type, options bindOptions, state *bindingState) (reflect.Value, bool) {
+	convertedValue, err := binder.convertValue(propertyName, propertyValue, typ, options)
+	if err == nil && !convertedValue.IsValid() {
+		err = NewPropertyConversionError(propertyName, propertyValue, typ.String(),
+			errors.New("no converter found for the type "+reflect.TypeOf(propertyValue).String()))
+	}
+	if err != nil {
+		binder.addFailure(state, propertyName, propertyValue, typ, err)
+		return reflect.Value{}, false
+	}
+	return convertedValue, true
+}
 
-		if err == nil {
-			value := bindTag.Value
-			splitResult := strings.Split(value, ",")
+func (binder ConfigurationPropertiesBinder) convertValue(propertyName string, propertyValue interface{}, typ reflect.Type, options bindOptions) (reflect.Value, error) {
+	if reflect.TypeOf(propertyValue) == typ {
+		return reflect.ValueOf(propertyValue), nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		elemValue, err := binder.convertValue(propertyName, propertyValue, typ.Elem(), options)
+		if err != nil || !elemValue.IsValid() {
+			return elemValue, err
+		}
+		pointerValue := reflect.New(typ.Elem())
+		pointerValue.Elem().Set(elemValue)
+		return pointerValue, nil
+	}
+	if typ.Kind() == reflect.Interface {
+		if propertyValue != nil && reflect.TypeOf(propertyValue).Implements(typ) {
+			return reflect.ValueOf(propertyValue).Convert(typ), nil
+		}
+		return reflect.Value{}, nil
+	} else if typ.Kind() == reflect.Func || typ.Kind() == reflect.Chan {
+		return reflect.Value{}, nil
+	}
+	if convertedValue, ok, err := convertSpecialValue(propertyValue, typ, options); ok {
+		if err != nil {
+			return reflect.Value{}, NewPropertyConversionError(propertyName, propertyValue, typ.String(), err)
+		}
+		return convertedValue, nil
+	}
+	propertyValueType := goo.GetType(propertyValue)
+	targetType := goo.GetType(reflect.Zero(typ).Interface())
+	if !binder.typeConverterService.CanConvert(propertyValueType, targetType) {
+		return reflect.Value{}, nil
+	}
+	convertedValue, err := binder.typeConverterService.Convert(propertyValue, propertyValueType, targetType)
+	if err != nil {
+		return reflect.Value{}, NewPropertyConversionError(propertyName, propertyValue, typ.String(), err)
+	}
+	result := reflect.ValueOf(convertedValue)
+	if !result.IsValid() || !result.Type().ConvertibleTo(typ) {
+		return reflect.Value{}, nil
+	}
+	return result.Convert(typ), nil
+}
 
-			tagValue := ""
-			omitEmpty := false
-			if len(splitResult) == 2 {
-				tagValue = strings.Trim(splitResult[0], " ")
-				if strings.Trim(splitResult[1], " ") == "omitempty" {
-					omitEmpty = true
-				}
+func (binder ConfigurationPropertiesBinder) bindCollection(propertyName string, value reflect.Value, options bindOptions, state *bindingState) bool {
+	typ := value.Type()
+	elements := make([]reflect.Value, 0)
+	failureCount := len(state.failures)
+	indices := binder.getIndices(state, propertyName)
+	if len(indices) != 0 {
+		/* indexed properties, e.g. servers[0].host or servers.0.host, only the given indices are bound */
+		indexedElements := make(map[int]reflect.Value, len(indices))
+		lastIndex := -1
+		for _, index := range indices {
+			indexedPropertyName := binder.getIndexedPropertyName(state, propertyName, index)
+			if typ.Kind() == reflect.Array && index >= typ.Len() {
+				binder.addFailure(state, indexedPropertyName, nil, typ, errors.New("index is out of the array length "+strconv.Itoa(typ.Len())))
+				continue
+			} else if index > maxCollectionIndex {
+				binder.addFailure(state, indexedPropertyName, nil, typ, errors.New("index exceeds the maximum index "+strconv.Itoa(maxCollectionIndex)))
+				continue
+			}
+			element := reflect.New(typ.Elem()).Elem()
+			binder.bindValue(indexedPropertyName, element, options.forElement(), state)
+			indexedElements[index] = element
+			lastIndex = index
+		}
+		for index := 0; index <= lastIndex; index++ {
+			if element, ok := indexedElements[index]; ok {
+				elements = append(elements, element)
 			} else {
-				tagValue = value
+				elements = append(elements, reflect.New(typ.Elem()).Elem())
 			}
-
-			defaultTag, err = field.GetTagByName("default")
-			if err != nil && value != "" {
-				binder.bindTargetField(field, target, binder.getFullPropertyName(prefix, tagValue), "", omitEmpty)
-			} else if err == nil && defaultTag.Value != "" {
-				binder.bindTargetField(field, target, binder.getFullPropertyName(prefix, tagValue), defaultTag.Value, omitEmpty)
+		}
+	} else if propertyValue, ok := binder.getProperty(state, propertyName, typ); ok || (options.defaultValue != "" && !state.ambiguities[propertyName]) {
+		/* comma-separated values, e.g. tags=a,b,c */
+		if !ok {
+			propertyValue = options.defaultValue
+		}
+		if propertyValue, ok = binder.resolvePlaceholders(state, propertyName, propertyValue, typ); !ok {
+			return false
+		}
+		convertedValue, err := binder.convertValue(propertyName, propertyValue, typ, options)
+		if err != nil {
+			binder.addFailure(state, propertyName, propertyValue, typ, err)
+			return false
+		} else if convertedValue.IsValid() {
+			value.Set(convertedValue)
+			return true
+		}
+		for index, item := range binder.splitPropertyValue(propertyValue) {
+			element, ok := binder.convertLeafValue(binder.getIndexedPropertyName(state, propertyName, index), item, typ.Elem(), options.forElement(), state)
+			if ok {
+				elements = append(elements, element)
 			}
 		}
+	} else {
+		return false
 	}
-	return nil
+
+	/* the collection is left untouched if any of its elements could not be bound */
+	if failureCount != len(state.failures) {
+		return false
+	}
+	if typ.Kind() == reflect.Array {
+		for index := 0; index < typ.Len() && index < len(elements); index++ {
+			value.Index(index).Set(elements[index])
+		}
+		return true
+	}
+	slice := reflect.MakeSlice(typ, len(elements), len(elements))
+	for index, element := range elements {
+		slice.Index(index).Set(element)
+	}
+	value.Set(slice)
+	return true
 }
 
-func (binder ConfigurationPropertiesBinder) bindTargetField(field goo.Field, instance interface{}, propertyName string, defaultValue string, omitEmpty bool) error {
-	propertyValue := binder.env.GetProperty(propertyName, defaultValue)
-	if propertyValue != nil {
+func (binder ConfigurationPropertiesBinder) splitPropertyValue(propertyValue interface{}) []interface{} {
+	items := make([]interface{}, 0)
+	if stringValue, ok := propertyValue.(string); ok {
+		if strings.TrimSpace(stringValue) == "" {
+			return items
+		}
+		for _, item := range strings.Split(stringValue, ",") {
+			items = append(items, strings.TrimSpace(item))
+		}
+		return items
+	}
+	value := reflect.ValueOf(propertyValue)
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		for index := 0; index < value.Len(); index++ {
+			items = append(items, value.Index(index).Interface())
+		}
+		return items
+	}
+	return append(items, propertyValue)
+}
 
-		switch propertyValue.(type) {
-		case string:
-			if omitEmpty && propertyValue.(string) == "" {
-				return nil
-			}
+func (binder ConfigurationPropertiesBinder) bindMap(propertyName string, value reflect.Value, options bindOptions, state *bindingState) bool {
+	typ := value.Type()
+	keys := binder.getMapKeys(state, propertyName, binder.isLeafType(typ.Elem()))
+	if len(keys) == 0 {
+		if options.defaultValue != "" && binder.isLeafType(typ.Elem()) {
+			return binder.bindMapDefaults(propertyName, value, options, state)
+		}
+		return false
+	}
+	if value.IsNil() {
+		value.Set(reflect.MakeMap(typ))
+	}
+	bound := false
+	for _, key := range keys {
+		keyValue, ok := binder.convertLeafValue(propertyName+"."+key, key, typ.Key(), bindOptions{}, state)
+		if !ok {
+			continue
 		}
+		element := reflect.New(typ.Elem()).Elem()
+		if existingElement := value.MapIndex(keyValue); existingElement.IsValid() {
+			element.Set(existingElement)
+		}
+		if binder.bindValue(propertyName+"."+key, element, options.forElement(), state) {
+			value.SetMapIndex(keyValue, element)
+			bound = true
+		}
+	}
+	return bound
+}
+
+func (binder ConfigurationPropertiesBinder) bindMapDefaults(propertyName string, value reflect.Value, options bindOptions, state *bindingState) bool {
+	/* default values of maps consist of comma-separated entries, e.g. key1=value1,key2=value2 */
+	typ := value.Type()
+	defaultValue, ok := binder.resolvePlaceholders(state, propertyName, options.defaultValue, typ)
+	if !ok {
+		return false
+	}
+	entries := reflect.MakeMap(typ)
+	failureCount := len(state.failures)
+	for _, entry := range strings.Split(defaultValue.(string), ",") {
+		index := strings.Index(entry, "=")
+		if index < 0 {
+			binder.addFailure(state, propertyName, options.defaultValue, typ, errors.New("invalid map entry '"+strings.TrimSpace(entry)+"'"))
+			continue
+		}
+		key := strings.TrimSpace(entry[:index])
+		keyValue, ok := binder.convertLeafValue(propertyName+"."+key, key, typ.Key(), bindOptions{}, state)
+		if !ok {
+			continue
+		}
+		elementValue, ok := binder.convertLeafValue(propertyName+"."+key, strings.TrimSpace(entry[index+1:]), typ.Elem(), options.forElement(), state)
+		if ok {
+			entries.SetMapIndex(keyValue, elementValue)
+		}
+	}
+	if failureCount != len(state.failures) {
+		return false
+	}
+	if value.IsNil() {
+		value.Set(entries)
+		return true
+	}
+	for _, key := range entries.MapKeys() {
+		value.SetMapIndex(key, entries.MapIndex(key))
+	}
+	return true
+}
 
-		if field.CanSet() {
-			propertyValueType := goo.GetType(propertyValue)
-			fieldType := field.GetType()
+func (binder ConfigurationPropertiesBinder) isLeafType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if isValueType(typ) {
+		return true
+	}
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
 
-			if propertyValueType.GetGoType() == fieldType.GetGoType() {
-				field.SetValue(instance, propertyValue)
-			} else if binder.typeConverterService.CanConvert(propertyValueType, fieldType) {
-				value, err := binder.typeConverterService.Convert(propertyValue, propertyValueType, fieldType)
-				if err != nil {
-					return err
-				}
-				field.SetValue(instance, value)
+func (binder ConfigurationPropertiesBinder) getMapKeys(state *bindingState, propertyName string, isLeafValue bool) []string {
+	keys := make([]string, 0)
+	processedKeys := make(map[string]bool, 0)
+	segmentCount := len(strings.Split(propertyName, "."))
+	for _, property := range state.properties.getPropertiesWithPrefix(propertyName) {
+		/* the keys keep the case of the property names, the segments before them are matched relaxed */
+		segments := strings.Split(property.dottedName, ".")
+		if len(segments) <= segmentCount {
+			continue
+		}
+		key := strings.Join(segments[segmentCount:], ".")
+		if !isLeafValue {
+			/* the key of a non-leaf value is only the first segment, the rest belongs to the value */
+			if index := strings.IndexAny(key, ".["); index > -1 {
+				key = key[:index]
 			}
 		}
+		if key != "" && !processedKeys[key] {
+			processedKeys[key] = true
+			keys = append(keys, key)
+		}
 	}
-	return nil
+	sort.Strings(keys)
+	return keys
+}
+
+func (binder ConfigurationPropertiesBinder) getIndices(state *bindingState, propertyName string) []int {
+	indices := make([]int, 0)
+	processedIndices := make(map[int]bool, 0)
+	uniformName := getUniformPropertyName(propertyName)
+	for _, property := range state.properties.getPropertiesWithPrefix(propertyName) {
+		rest := property.uniformName[len(uniformName)+1:]
+		var indexText string
+		if property.uniformName[len(uniformName)] == '[' {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				continue
+			}
+			indexText = rest[:end]
+		} else {
+			if end := strings.IndexAny(rest, ".["); end > -1 {
+				rest = rest[:end]
+			}
+			indexText = rest
+		}
+		index, err := strconv.Atoi(indexText)
+		if err != nil || index < 0 || processedIndices[index] {
+			continue
+		}
+		processedIndices[index] = true
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+func (binder ConfigurationPropertiesBinder) getIndexedPropertyName(state *bindingState, propertyName string, index int) string {
+	indexedName := propertyName + "[" + strconv.Itoa(index) + "]"
+	if binder.containsProperty(state, indexedName) || binder.containsPropertiesWithPrefix(state, indexedName) {
+		return indexedName
+	}
+	dottedName := propertyName + "." + strconv.Itoa(index)
+	if binder.containsProperty(state, dottedName) || binder.containsPropertiesWithPrefix(state, dottedName) {
+		return dottedName
+	}
+	return indexedName
+}
+
+func (binder ConfigurationPropertiesBinder) containsPropertiesWithPrefix(state *bindingState, prefix string) bool {
+	for _, property := range state.properties.getPropertiesWithPrefix(prefix) {
+		if strings.HasPrefix(property.uniformName, getUniformPropertyName(prefix)+".") {
+			return true
+		}
+	}
+	return false
 }
 
 func (binder ConfigurationPropertiesBinder) getFullPropertyName(prefix string, tagValue string) string {
-	return prefix + "." + tagValue
+	return prefix + "." + GetCanonicalPropertyName(tagValue)
 }
@@ -1,9 +1,11 @@
 package context
 
 import (
+	"errors"
 	core "github.com/procyon-projects/procyon-core"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -115,3 +117,269 @@ func TestConfigurationPropertiesBinder_BindWithNonConfigurationPropertiesInstanc
 	err := propertiesBindingProcessor.Bind(testContext{})
 	assert.Nil(t, err)
 }
+
+type testRequiredProperties struct {
+	Url  string `json:"url" required:"true"`
+	Port uint16 `json:"port" default:"8080"`
+}
+
+func (testRequiredProperties) GetConfigurationPrefix() string {
+	return "server"
+}
+
+func TestConfigurationPropertiesBinder_BindWithMissingRequiredProperty(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	err := propertiesBindingProcessor.Bind(&testRequiredProperties{})
+	assert.NotNil(t, err)
+	validationError, ok := err.(ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationError.GetFailures()))
+	assert.Equal(t, "server.url", validationError.GetFailures()[0].GetPropertyName())
+	assert.Equal(t, "required", validationError.GetFailures()[0].GetConstraint())
+}
+
+func TestConfigurationPropertiesBinder_BindWithInvalidValue(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app", "--server.url=localhost", "--server.port=invalid"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	err := propertiesBindingProcessor.Bind(&testRequiredProperties{})
+	assert.NotNil(t, err)
+	var conversionError PropertyConversionError
+	assert.True(t, errors.As(err, &conversionError))
+	assert.Equal(t, "server.port", conversionError.GetPropertyName())
+	assert.Equal(t, "invalid", conversionError.GetValue())
+	assert.Equal(t, "uint16", conversionError.GetTypeName())
+}
+
+type testDatabaseProperties struct {
+	Host string `json:"host" default:"localhost"`
+	Port int    `json:"port"`
+}
+
+type testPoolProperties struct {
+	MaxSize int `json:"max-size"`
+}
+
+type testNode struct {
+	Name string    `json:"name"`
+	Next *testNode `json:"next"`
+}
+
+type testNestedProperties struct {
+	Name     string                 `json:"name"`
+	Database testDatabaseProperties `json:"database"`
+	Pool     *testPoolProperties    `json:"pool"`
+	Cache    *testPoolProperties    `json:"cache"`
+	Node     testNode               `json:"node"`
+	Ignored  testPoolProperties     `json:"-"`
+}
+
+func (testNestedProperties) GetConfigurationPrefix() string {
+	return "app"
+}
+
+func TestConfigurationPropertiesBinder_BindNestedStructs(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.name=test-app", "--app.database.port=5432", "--app.pool.max-size=10", "--app.node.name=first",
+		"--app.node.next.name=second"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testNestedProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	assert.Nil(t, err)
+	assert.Equal(t, "test-app", properties.Name)
+	assert.Equal(t, "localhost", properties.Database.Host)
+	assert.Equal(t, 5432, properties.Database.Port)
+	assert.NotNil(t, properties.Pool)
+	assert.Equal(t, 10, properties.Pool.MaxSize)
+	assert.Nil(t, properties.Cache)
+	assert.Equal(t, "first", properties.Node.Name)
+	assert.NotNil(t, properties.Node.Next)
+	assert.Equal(t, "second", properties.Node.Next.Name)
+	assert.Nil(t, properties.Node.Next.Next)
+}
+
+type testServerProperties struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type testCollectionProperties struct {
+	Tags        []string                        `json:"tags"`
+	Ports       []int                           `json:"ports" default:"80,443"`
+	Weights     [3]int                          `json:"weights"`
+	Servers     []testServerProperties          `json:"servers"`
+	Backups     []*testServerProperties         `json:"backups"`
+	Labels      map[string]string               `json:"labels"`
+	Limits      map[string]int                  `json:"limits"`
+	Clusters    map[string]testServerProperties `json:"clusters"`
+	Environment map[string]interface{}          `json:"environment"`
+}
+
+func (testCollectionProperties) GetConfigurationPrefix() string {
+	return "app"
+}
+
+func TestConfigurationPropertiesBinder_BindCollections(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.tags=a, b,c",
+		"--app.weights[0]=1", "--app.weights[2]=3",
+		"--app.servers[0].host=first", "--app.servers[0].port=8080", "--app.servers[1].host=second",
+		"--app.backups.0.host=backup",
+		"--app.labels.env=prod", "--app.labels.team.name=core",
+		"--app.limits.cpu=2",
+		"--app.clusters.eu.host=eu-host", "--app.clusters.us.port=9090",
+		"--app.environment.mode=debug",
+	}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testCollectionProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, properties.Tags)
+	assert.Equal(t, []int{80, 443}, properties.Ports)
+	assert.Equal(t, [3]int{1, 0, 3}, properties.Weights)
+	assert.Equal(t, []testServerProperties{{"first", 8080}, {"second", 0}}, properties.Servers)
+	assert.Equal(t, 1, len(properties.Backups))
+	assert.Equal(t, "backup", properties.Backups[0].Host)
+	assert.Equal(t, map[string]string{"env": "prod", "team.name": "core"}, properties.Labels)
+	assert.Equal(t, map[string]int{"cpu": 2}, properties.Limits)
+	assert.Equal(t, map[string]testServerProperties{"eu": {"eu-host", 0}, "us": {"", 9090}}, properties.Clusters)
+	assert.Equal(t, map[string]interface{}{"mode": "debug"}, properties.Environment)
+}
+
+func TestConfigurationPropertiesBinder_BindCollectionsWithOutOfRangeIndices(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.tags[2]=c", "--app.tags[0]=a",
+		"--app.weights[1]=2", "--app.weights[3]=4",
+	}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testCollectionProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	bindError, ok := err.(BindError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(bindError.GetFailures()))
+	assert.Equal(t, "app.weights[3]", bindError.GetFailures()[0].GetPropertyName())
+	assert.Equal(t, []string{"a", "", "c"}, properties.Tags)
+
+	/* a huge index must not allocate the whole collection */
+	standardEnvironment = core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.tags[99999999]=x",
+	}))
+	propertiesBindingProcessor = newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties = &testCollectionProperties{}
+	err = propertiesBindingProcessor.Bind(properties)
+	bindError, ok = err.(BindError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(bindError.GetFailures()))
+	assert.Equal(t, "app.tags[99999999]", bindError.GetFailures()[0].GetPropertyName())
+	assert.Nil(t, properties.Tags)
+}
+
+type testInvalidProperties struct {
+	Url     string         `json:"url" required:"true"`
+	Port    uint16         `json:"port"`
+	Timeout int            `json:"timeout"`
+	Ports   []int          `json:"ports"`
+	Limits  map[string]int `json:"limits"`
+	Handler func()         `json:"handler"`
+}
+
+func (testInvalidProperties) GetConfigurationPrefix() string {
+	return "server"
+}
+
+func TestConfigurationPropertiesBinder_BindWithMultipleFailures(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--server.port=80x", "--server.timeout=30", "--server.ports=80,x,443", "--server.limits.cpu=two", "--server.handler=test"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testInvalidProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	assert.NotNil(t, err)
+	bindError, ok := err.(BindError)
+	assert.True(t, ok)
+	assert.Equal(t, "server", bindError.GetPrefix())
+
+	failures := bindError.GetFailures()
+	assert.Equal(t, 4, len(failures))
+	assert.Equal(t, "server.port", failures[0].GetPropertyName())
+	assert.Equal(t, "80x", failures[0].GetValue())
+	assert.Equal(t, "uint16", failures[0].GetTypeName())
+	assert.NotEqual(t, "", failures[0].GetSource())
+	assert.Equal(t, "server.ports[1]", failures[1].GetPropertyName())
+	assert.Equal(t, "x", failures[1].GetValue())
+	assert.Equal(t, "server.limits.cpu", failures[2].GetPropertyName())
+	assert.Equal(t, "server.handler", failures[3].GetPropertyName())
+	assert.Equal(t, "func()", failures[3].GetTypeName())
+
+	assert.Equal(t, 30, properties.Timeout)
+	assert.Nil(t, properties.Ports)
+	assert.True(t, strings.Contains(err.Error(), "80x"))
+}
+
+type testTaggedPoolProperties struct {
+	MaxSize int `default:"10"`
+}
+
+type testTaggedProperties struct {
+	Name        string                    `property:"app-name" json:"name"`
+	DisplayName string                    `json:"display-name,omitempty,string" default:"Test"`
+	Version     string                    `default:"1.0"`
+	Ignored     string                    `property:"-" json:"ignored"`
+	Owner       string                    `property:",omitempty" default:"owner"`
+	Labels      map[string]string         `json:"labels" default:"env=test, team=core"`
+	Limits      map[string]int            `json:"limits" default:"cpu=2"`
+	Pool        testTaggedPoolProperties  `json:"pool"`
+	Backup      *testTaggedPoolProperties `json:"backup"`
+	Untagged    string
+}
+
+func (testTaggedProperties) GetConfigurationPrefix() string {
+	return "tagged"
+}
+
+func TestConfigurationPropertiesBinder_BindWithTags(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--tagged.name=json-name", "--tagged.app-name=property-name", "--tagged.ignored=value", "--tagged.owner=",
+		"--tagged.limits.memory=4", "--tagged.untagged=value"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testTaggedProperties{}
+	assert.Nil(t, propertiesBindingProcessor.Bind(properties))
+	assert.Equal(t, "property-name", properties.Name)
+	assert.Equal(t, "Test", properties.DisplayName)
+	assert.Equal(t, "1.0", properties.Version)
+	assert.Equal(t, "", properties.Ignored)
+	assert.Equal(t, "owner", properties.Owner)
+	assert.Equal(t, map[string]string{"env": "test", "team": "core"}, properties.Labels)
+	assert.Equal(t, map[string]int{"memory": 4}, properties.Limits)
+	assert.Equal(t, 10, properties.Pool.MaxSize)
+	assert.Nil(t, properties.Backup)
+	assert.Equal(t, "", properties.Untagged)
+}
+
+func TestConfigurationPropertiesBinder_BindWithDefaultOnlyFields(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--tagged.version=2.0", "--tagged.backup.max-size=5"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testTaggedProperties{}
+	assert.Nil(t, propertiesBindingProcessor.Bind(properties))
+	assert.Equal(t, "2.0", properties.Version)
+	assert.NotNil(t, properties.Backup)
+	assert.Equal(t, 5, properties.Backup.MaxSize)
+}
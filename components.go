@@ -1,28 +1,197 @@
 package context
 
 import (
+	"errors"
 	"github.com/procyon-projects/goo"
 	core "github.com/procyon-projects/procyon-core"
 	peas "github.com/procyon-projects/procyon-peas"
+	"sort"
 	"strings"
 )
 
+type ScopedComponent interface {
+	GetPeaScope() peas.PeaScope
+}
+
+type LazyInitComponent interface {
+	IsLazyInit() bool
+}
+
+type NamedComponent interface {
+	GetPeaName() string
+}
+
+type AliasedComponent interface {
+	GetPeaAliases() []string
+}
+
+type PrimaryComponent interface {
+	IsPrimary() bool
+}
+
+type QualifiedComponent interface {
+	GetQualifiers() []string
+}
+
+const PeaNameGeneratorProperty = "procyon.context.pea-name-generator"
+
+const (
+	SimplePeaNameGenerator         = "simple"
+	FullyQualifiedPeaNameGenerator = "fully-qualified"
+)
+
+type PeaNameCollisionError struct {
+	peaName          string
+	existingTypeName string
+	typeName         string
+}
+
+func NewPeaNameCollisionError(peaName string, existingTypeName string, typeName string) PeaNameCollisionError {
+	return PeaNameCollisionError{
+		peaName,
+		existingTypeName,
+		typeName,
+	}
+}
+
+func (err PeaNameCollisionError) GetPeaName() string {
+	return err.peaName
+}
+
+func (err PeaNameCollisionError) GetExistingTypeName() string {
+	return err.existingTypeName
+}
+
+func (err PeaNameCollisionError) GetTypeName() string {
+	return err.typeName
+}
+
+func (err PeaNameCollisionError) Error() string {
+	return "pea name '" + err.peaName + "' of the type " + err.typeName +
+		" conflicts with the existing pea of the type " + err.existingTypeName
+}
+
+type ScannedPeaDefinitionOption func(definition *ScannedPeaDefinition)
+
 type ScannedPeaDefinition struct {
 	*peas.SimplePeaDefinition
-	componentName string
+	componentName  string
+	componentScope peas.PeaScope
+	lazyInit       *bool
+	peaName        string
+	aliases        []string
+	primary        bool
+	qualifiers     []string
 }
 
-func NewScannedPeaDefinition(componentName string, peaType goo.Type) ScannedPeaDefinition {
-	return ScannedPeaDefinition{
-		peas.NewSimplePeaDefinition(peaType),
-		componentName,
+func NewScannedPeaDefinition(componentName string, peaType goo.Type, options ...ScannedPeaDefinitionOption) ScannedPeaDefinition {
+	definition := ScannedPeaDefinition{
+		SimplePeaDefinition: peas.NewSimplePeaDefinition(peaType),
+		componentName:       componentName,
 	}
+
+	for _, option := range options {
+		option(&definition)
+	}
+
+	if definition.componentScope == "" {
+		definition.componentScope = peas.SharedScope
+	}
+	return definition
 }
 
 func (definition ScannedPeaDefinition) GetComponentName() string {
 	return definition.componentName
 }
 
+func (definition ScannedPeaDefinition) GetScope() peas.PeaScope {
+	if definition.componentScope == peas.SharedScope {
+		return peas.SharedScope
+	}
+	/* the instances of the custom scopes are created as prototypes and cached by their scopes */
+	return peas.PrototypeScope
+}
+
+func (definition ScannedPeaDefinition) GetComponentScope() peas.PeaScope {
+	return definition.componentScope
+}
+
+func (definition ScannedPeaDefinition) IsLazyInit(defaultLazyInit bool) bool {
+	if definition.lazyInit == nil {
+		return defaultLazyInit
+	}
+	return *definition.lazyInit
+}
+
+func (definition ScannedPeaDefinition) GetPeaName() string {
+	return definition.peaName
+}
+
+func (definition ScannedPeaDefinition) GetAliases() []string {
+	return definition.aliases
+}
+
+func (definition ScannedPeaDefinition) IsPrimary() bool {
+	return definition.primary
+}
+
+func (definition ScannedPeaDefinition) GetQualifiers() []string {
+	return definition.qualifiers
+}
+
+func (definition ScannedPeaDefinition) HasQualifier(qualifier string) bool {
+	for _, peaQualifier := range definition.qualifiers {
+		if peaQualifier == qualifier {
+			return true
+		}
+	}
+	return false
+}
+
+func WithPrimary(primary bool) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.primary = primary
+	}
+}
+
+func WithQualifiers(qualifiers ...string) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.qualifiers = append(definition.qualifiers, qualifiers...)
+	}
+}
+
+func WithPeaName(peaName string) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.peaName = peaName
+	}
+}
+
+func WithAliases(aliases ...string) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.aliases = append(definition.aliases, aliases...)
+	}
+}
+
+func WithLazyInit(lazyInit bool) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.lazyInit = &lazyInit
+	}
+}
+
+func WithComponentScope(scope peas.PeaScope) ScannedPeaDefinitionOption {
+	return func(definition *ScannedPeaDefinition) {
+		definition.componentScope = scope
+	}
+}
+
+func newComponentInstance(componentType goo.Type) interface{} {
+	returnType := componentType
+	if componentType.IsFunction() {
+		returnType = componentType.ToFunctionType().GetFunctionReturnTypes()[0]
+	}
+	return returnType.ToStructType().NewInstance()
+}
+
 type ScannedPeaNameGenerator struct {
 }
 
@@ -31,6 +200,9 @@ func NewScannedPeaNameGenerator() ScannedPeaNameGenerator {
 }
 
 func (generator ScannedPeaNameGenerator) GenerateName(peaDefinition peas.PeaDefinition) string {
+	if peaName := getExplicitPeaName(peaDefinition); peaName != "" {
+		return peaName
+	}
 	peaTypeName := peaDefinition.GetTypeName()
 	lastDotIndex := strings.LastIndex(peaTypeName, ".")
 	shortName := ""
@@ -43,31 +215,221 @@ func (generator ScannedPeaNameGenerator) GenerateName(peaDefinition peas.PeaDefi
 	return shortName
 }
 
+type FullyQualifiedScannedPeaNameGenerator struct {
+}
+
+func NewFullyQualifiedScannedPeaNameGenerator() FullyQualifiedScannedPeaNameGenerator {
+	return FullyQualifiedScannedPeaNameGenerator{}
+}
+
+func (generator FullyQualifiedScannedPeaNameGenerator) GenerateName(peaDefinition peas.PeaDefinition) string {
+	if peaName := getExplicitPeaName(peaDefinition); peaName != "" {
+		return peaName
+	}
+	return peaDefinition.GetTypeName()
+}
+
+func getExplicitPeaName(peaDefinition peas.PeaDefinition) string {
+	if scannedPeaDefinition, ok := peaDefinition.(ScannedPeaDefinition); ok {
+		return scannedPeaDefinition.GetPeaName()
+	}
+	return ""
+}
+
+func getPeaNameGenerator(env core.Environment) peas.PeaNameGenerator {
+	if env != nil {
+		generatorName := env.GetProperty(PeaNameGeneratorProperty, SimplePeaNameGenerator)
+		if generatorName == FullyQualifiedPeaNameGenerator {
+			return NewFullyQualifiedScannedPeaNameGenerator()
+		}
+	}
+	return NewScannedPeaNameGenerator()
+}
+
 type ComponentPeaDefinitionScanner struct {
 	peaNameGenerator peas.PeaNameGenerator
 	peaRegistry      peas.PeaDefinitionRegistry
+	env              core.Environment
+	report           *ConditionEvaluationReport
+	aliases          map[string]ScannedPeaDefinition
+	filters          *ComponentFilters
 }
 
-func NewComponentPeaDefinitionScanner(registry peas.PeaDefinitionRegistry) ComponentPeaDefinitionScanner {
+func NewComponentPeaDefinitionScanner(registry peas.PeaDefinitionRegistry, env core.Environment) ComponentPeaDefinitionScanner {
 	return ComponentPeaDefinitionScanner{
-		NewScannedPeaNameGenerator(),
+		getPeaNameGenerator(env),
 		registry,
+		env,
+		NewConditionEvaluationReport(),
+		make(map[string]ScannedPeaDefinition, 0),
+		NewComponentFilters(),
 	}
 }
 
-func (scanner ComponentPeaDefinitionScanner) DoScan() {
-	scannedPeaDefinitions := make([]ScannedPeaDefinition, 0)
+func (scanner ComponentPeaDefinitionScanner) GetComponentFilters() *ComponentFilters {
+	return scanner.filters
+}
+
+func (scanner ComponentPeaDefinitionScanner) GetConditionEvaluationReport() *ConditionEvaluationReport {
+	return scanner.report
+}
+
+func (scanner ComponentPeaDefinitionScanner) DoScan() error {
+	componentTypes := make(map[string]goo.Type, 0)
 	core.ForEachComponentType(func(componentName string, componentType goo.Type) error {
-		scannedPeaDefinition := NewScannedPeaDefinition(componentName, componentType)
-		scannedPeaDefinitions = append(scannedPeaDefinitions, scannedPeaDefinition)
+		componentTypes[componentName] = componentType
 		return nil
 	})
+	return scanner.scanComponents(componentTypes)
+}
+
+func (scanner ComponentPeaDefinitionScanner) scanComponents(componentTypes map[string]goo.Type) error {
+	componentNames := make([]string, 0, len(componentTypes))
+	for componentName := range componentTypes {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+
+	filters, err := getComponentFiltersFromEnvironment(scanner.env)
+	if err != nil {
+		return err
+	}
+	filters.AddAll(scanner.filters)
 
-	for _, peaDefinition := range scannedPeaDefinitions {
-		peaName := scanner.peaNameGenerator.GenerateName(peaDefinition)
-		if !scanner.peaRegistry.ContainsPeaDefinition(peaName) {
-			peaDefinitionHolder := peas.NewPeaDefinitionHolder(peaName, peaDefinition)
-			scanner.peaRegistry.RegisterPeaDefinition(peaName, peaDefinitionHolder.GetPeaDefinition())
+	conditionalComponentNames := make([]string, 0)
+	for _, componentName := range componentNames {
+		componentType := componentTypes[componentName]
+		if !filters.matches(componentName, componentType, !isContextComponent(componentType)) {
+			continue
 		}
+		isCandidate, err := scanner.isCandidateComponent(componentType)
+		if err != nil {
+			return err
+		}
+		if !isCandidate {
+			continue
+		}
+		if _, ok := newComponentInstance(componentType).(ConditionalComponent); ok {
+			conditionalComponentNames = append(conditionalComponentNames, componentName)
+			continue
+		}
+		err = scanner.registerPeaDefinition(scanner.createPeaDefinition(componentName, componentType))
+		if err != nil {
+			return err
+		}
+	}
+
+	/* conditional components are registered after the others so that their conditions can see them */
+	for _, componentName := range conditionalComponentNames {
+		componentType := componentTypes[componentName]
+		if !scanner.matchesConditions(componentName, componentType) {
+			continue
+		}
+		err := scanner.registerPeaDefinition(scanner.createPeaDefinition(componentName, componentType))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (scanner ComponentPeaDefinitionScanner) registerPeaDefinition(peaDefinition ScannedPeaDefinition) error {
+	peaName := scanner.peaNameGenerator.GenerateName(peaDefinition)
+	if scanner.peaRegistry.ContainsPeaDefinition(peaName) {
+		existingPeaDefinition := scanner.peaRegistry.GetPeaDefinition(peaName)
+		if existingPeaDefinition != nil && existingPeaDefinition.GetTypeName() != peaDefinition.GetTypeName() {
+			return NewPeaNameCollisionError(peaName, existingPeaDefinition.GetTypeName(), peaDefinition.GetTypeName())
+		}
+		return nil
+	}
+
+	if aliasedPeaDefinition, ok := scanner.aliases[peaName]; ok {
+		return NewPeaNameCollisionError(peaName, aliasedPeaDefinition.GetTypeName(), peaDefinition.GetTypeName())
+	}
+
+	for _, alias := range peaDefinition.GetAliases() {
+		if aliasedPeaDefinition, ok := scanner.aliases[alias]; ok {
+			return NewPeaNameCollisionError(alias, aliasedPeaDefinition.GetTypeName(), peaDefinition.GetTypeName())
+		}
+		if scanner.peaRegistry.ContainsPeaDefinition(alias) {
+			return NewPeaNameCollisionError(alias, scanner.peaRegistry.GetPeaDefinition(alias).GetTypeName(), peaDefinition.GetTypeName())
+		}
+	}
+
+	for _, alias := range peaDefinition.GetAliases() {
+		scanner.aliases[alias] = peaDefinition
+	}
+	peaDefinitionHolder := peas.NewPeaDefinitionHolder(peaName, peaDefinition)
+	scanner.peaRegistry.RegisterPeaDefinition(peaName, peaDefinitionHolder.GetPeaDefinition())
+	return nil
+}
+
+func findPeaDefinitionByAlias(registry peas.PeaDefinitionRegistry, alias string) (string, peas.PeaDefinition) {
+	for _, peaName := range registry.GetPeaDefinitionNames() {
+		if scannedPeaDefinition, ok := registry.GetPeaDefinition(peaName).(ScannedPeaDefinition); ok {
+			for _, peaAlias := range scannedPeaDefinition.GetAliases() {
+				if peaAlias == alias {
+					return peaName, scannedPeaDefinition
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+func (scanner ComponentPeaDefinitionScanner) matchesConditions(componentName string, componentType goo.Type) bool {
+	conditionalComponent := newComponentInstance(componentType).(ConditionalComponent)
+	for _, condition := range conditionalComponent.GetConditions() {
+		if condition == nil {
+			continue
+		}
+		outcome := condition.Matches(scanner.peaRegistry, scanner.env)
+		scanner.report.RecordEvaluation(componentName, outcome)
+		if !outcome.IsMatched() {
+			return false
+		}
+	}
+	return true
+}
+
+func (scanner ComponentPeaDefinitionScanner) isCandidateComponent(componentType goo.Type) (bool, error) {
+	initializerType := goo.GetType((*ApplicationContextInitializer)(nil)).ToInterfaceType()
+	returnType := componentType.ToFunctionType().GetFunctionReturnTypes()[0]
+	if returnType.ToStructType().Implements(initializerType) {
+		return false, nil
+	}
+
+	instance := newComponentInstance(componentType)
+	if profileComponent, ok := instance.(ProfileComponent); ok && len(profileComponent.GetProfiles()) != 0 {
+		accepted, err := AcceptsProfiles(scanner.env, profileComponent.GetProfiles()...)
+		if err != nil {
+			return false, errors.New("invalid profiles for the component " + returnType.GetFullName() + " : " + err.Error())
+		}
+		return accepted, nil
+	}
+	return true, nil
+}
+
+func (scanner ComponentPeaDefinitionScanner) createPeaDefinition(componentName string, componentType goo.Type) ScannedPeaDefinition {
+	options := make([]ScannedPeaDefinitionOption, 0)
+	instance := newComponentInstance(componentType)
+	if scopedComponent, ok := instance.(ScopedComponent); ok && scopedComponent.GetPeaScope() != "" {
+		options = append(options, WithComponentScope(scopedComponent.GetPeaScope()))
+	}
+	if lazyInitComponent, ok := instance.(LazyInitComponent); ok {
+		options = append(options, WithLazyInit(lazyInitComponent.IsLazyInit()))
+	}
+	if namedComponent, ok := instance.(NamedComponent); ok && namedComponent.GetPeaName() != "" {
+		options = append(options, WithPeaName(namedComponent.GetPeaName()))
+	}
+	if aliasedComponent, ok := instance.(AliasedComponent); ok {
+		options = append(options, WithAliases(aliasedComponent.GetPeaAliases()...))
+	}
+	if primaryComponent, ok := instance.(PrimaryComponent); ok {
+		options = append(options, WithPrimary(primaryComponent.IsPrimary()))
+	}
+	if qualifiedComponent, ok := instance.(QualifiedComponent); ok {
+		options = append(options, WithQualifiers(qualifiedComponent.GetQualifiers()...))
 	}
+	return NewScannedPeaDefinition(componentName, componentType, options...)
 }
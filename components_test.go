@@ -2,9 +2,11 @@ package context
 
 import (
 	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
 	peas "github.com/procyon-projects/procyon-peas"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"strings"
 	"testing"
 )
 
@@ -71,7 +73,191 @@ func TestComponentPeaDefinitionScanner_DoScan(t *testing.T) {
 	mockRegistry.On("ContainsPeaDefinition", mock.AnythingOfType("string")).Return(false)
 	mockRegistry.On("RegisterPeaDefinition", mock.AnythingOfType("string"), mock.AnythingOfType("context.ScannedPeaDefinition")).Return(false)
 
-	scanner := NewComponentPeaDefinitionScanner(mockRegistry)
+	scanner := NewComponentPeaDefinitionScanner(mockRegistry, core.NewStandardEnvironment())
 	scanner.DoScan()
 	mockRegistry.AssertExpectations(t)
 }
+
+func TestScannedPeaDefinition_WithComponentScope(t *testing.T) {
+	definition := NewScannedPeaDefinition("testComponent", goo.GetType(newTestScopedComponent), WithComponentScope(RequestScope))
+	assert.Equal(t, RequestScope, definition.GetComponentScope())
+	assert.Equal(t, peas.PrototypeScope, definition.GetScope())
+
+	definition = NewScannedPeaDefinition("testComponent", goo.GetType(newTestScopedComponent), WithComponentScope(peas.PrototypeScope))
+	assert.Equal(t, peas.PrototypeScope, definition.GetComponentScope())
+	assert.Equal(t, peas.PrototypeScope, definition.GetScope())
+}
+
+func TestComponentPeaDefinitionScanner_CreatePeaDefinition(t *testing.T) {
+	scanner := NewComponentPeaDefinitionScanner(&mockPeaRegistry{}, core.NewStandardEnvironment())
+	definition := scanner.createPeaDefinition("testComponent", goo.GetType(newTestScopedComponent))
+	assert.Equal(t, RequestScope, definition.GetComponentScope())
+}
+
+type testDevComponent struct {
+}
+
+func newTestDevComponent() testDevComponent {
+	return testDevComponent{}
+}
+
+func (component testDevComponent) GetProfiles() []string {
+	return []string{"dev"}
+}
+
+type testNotDevComponent struct {
+}
+
+func newTestNotDevComponent() testNotDevComponent {
+	return testNotDevComponent{}
+}
+
+func (component testNotDevComponent) GetProfiles() []string {
+	return []string{"!dev"}
+}
+
+type testInvalidProfileComponent struct {
+}
+
+func newTestInvalidProfileComponent() testInvalidProfileComponent {
+	return testInvalidProfileComponent{}
+}
+
+func (component testInvalidProfileComponent) GetProfiles() []string {
+	return []string{"dev &"}
+}
+
+func TestComponentPeaDefinitionScanner_ScanComponentsWithProfiles(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	scanner := NewComponentPeaDefinitionScanner(registry, newTestProfileEnvironment("dev"))
+	err := scanner.scanComponents(map[string]goo.Type{
+		"testDevComponent":    goo.GetType(newTestDevComponent),
+		"testNotDevComponent": goo.GetType(newTestNotDevComponent),
+	})
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("testDevComponent"))
+	assert.False(t, registry.ContainsPeaDefinition("testNotDevComponent"))
+
+	err = scanner.scanComponents(map[string]goo.Type{
+		"testInvalidProfileComponent": goo.GetType(newTestInvalidProfileComponent),
+	})
+	assert.NotNil(t, err)
+}
+
+type testNamedService struct {
+}
+
+func newTestNamedService() testNamedService {
+	return testNamedService{}
+}
+
+func (service testNamedService) GetPeaName() string {
+	return "service"
+}
+
+func (service testNamedService) GetPeaAliases() []string {
+	return []string{"namedService", "mainService"}
+}
+
+type testAnotherNamedService struct {
+}
+
+func newTestAnotherNamedService() testAnotherNamedService {
+	return testAnotherNamedService{}
+}
+
+func (service testAnotherNamedService) GetPeaName() string {
+	return "service"
+}
+
+type testAliasCollisionService struct {
+}
+
+func newTestAliasCollisionService() testAliasCollisionService {
+	return testAliasCollisionService{}
+}
+
+func (service testAliasCollisionService) GetPeaAliases() []string {
+	return []string{"mainService"}
+}
+
+func TestComponentPeaDefinitionScanner_ExplicitNameAndAliases(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	scanner := NewComponentPeaDefinitionScanner(registry, core.NewStandardEnvironment())
+	err := scanner.scanComponents(map[string]goo.Type{
+		"testNamedService": goo.GetType(newTestNamedService),
+	})
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("service"))
+	definition := registry.GetPeaDefinition("service").(ScannedPeaDefinition)
+	assert.Equal(t, "service", definition.GetPeaName())
+	assert.Equal(t, []string{"namedService", "mainService"}, definition.GetAliases())
+
+	err = scanner.scanComponents(map[string]goo.Type{
+		"testAnotherNamedService": goo.GetType(newTestAnotherNamedService),
+	})
+	assert.NotNil(t, err)
+	collisionError, ok := err.(PeaNameCollisionError)
+	assert.True(t, ok)
+	assert.Equal(t, "service", collisionError.GetPeaName())
+	assert.Equal(t, "github.com.procyon.projects.procyon.context.testNamedService", collisionError.GetExistingTypeName())
+	assert.Equal(t, "github.com.procyon.projects.procyon.context.testAnotherNamedService", collisionError.GetTypeName())
+	assert.True(t, strings.Contains(err.Error(), "testAnotherNamedService"))
+
+	err = scanner.scanComponents(map[string]goo.Type{
+		"testAliasCollisionService": goo.GetType(newTestAliasCollisionService),
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, "mainService", err.(PeaNameCollisionError).GetPeaName())
+}
+
+func TestFullyQualifiedScannedPeaNameGenerator_GenerateName(t *testing.T) {
+	definition := NewScannedPeaDefinition("testComponent", goo.GetType(NewConfigurationPropertiesBindingProcessor))
+	generator := NewFullyQualifiedScannedPeaNameGenerator()
+	assert.Equal(t, "github.com.procyon.projects.procyon.context.ConfigurationPropertiesBindingProcessor", generator.GenerateName(definition))
+
+	definition = NewScannedPeaDefinition("testComponent", goo.GetType(NewConfigurationPropertiesBindingProcessor), WithPeaName("processor"))
+	assert.Equal(t, "processor", generator.GenerateName(definition))
+	assert.Equal(t, "processor", NewScannedPeaNameGenerator().GenerateName(definition))
+}
+
+func TestComponentPeaDefinitionScanner_WithFullyQualifiedPeaNameGenerator(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app", "--" + PeaNameGeneratorProperty + "=" + FullyQualifiedPeaNameGenerator}))
+	scanner := NewComponentPeaDefinitionScanner(registry, env)
+	err := scanner.scanComponents(map[string]goo.Type{
+		"testLazyComponent": goo.GetType(newTestLazyComponent),
+	})
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("github.com.procyon.projects.procyon.context.testLazyComponent"))
+}
+
+type testPrimaryService struct {
+}
+
+func newTestPrimaryService() testPrimaryService {
+	return testPrimaryService{}
+}
+
+func (service testPrimaryService) IsPrimary() bool {
+	return true
+}
+
+func (service testPrimaryService) GetQualifiers() []string {
+	return []string{"main", "default"}
+}
+
+func TestComponentPeaDefinitionScanner_PrimaryAndQualifiers(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	scanner := NewComponentPeaDefinitionScanner(registry, core.NewStandardEnvironment())
+	definition := scanner.createPeaDefinition("testPrimaryService", goo.GetType(newTestPrimaryService))
+	assert.True(t, definition.IsPrimary())
+	assert.Equal(t, []string{"main", "default"}, definition.GetQualifiers())
+	assert.True(t, definition.HasQualifier("default"))
+	assert.False(t, definition.HasQualifier("other"))
+
+	definition = scanner.createPeaDefinition("testNamedService", goo.GetType(newTestNamedService))
+	assert.False(t, definition.IsPrimary())
+	assert.Equal(t, 0, len(definition.GetQualifiers()))
+}
@@ -0,0 +1,166 @@
+package context
+
+import (
+	"fmt"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"strings"
+	"sync"
+)
+
+const conditionEvaluationReport = "github.com.procyon.context.conditionEvaluationReport"
+
+type Condition interface {
+	Matches(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome
+}
+
+type ConditionalComponent interface {
+	GetConditions() []Condition
+}
+
+type ConditionOutcome struct {
+	matched bool
+	message string
+}
+
+func NewConditionOutcome(matched bool, message string) ConditionOutcome {
+	return ConditionOutcome{
+		matched,
+		message,
+	}
+}
+
+func (outcome ConditionOutcome) IsMatched() bool {
+	return outcome.matched
+}
+
+func (outcome ConditionOutcome) GetMessage() string {
+	return outcome.message
+}
+
+type ConditionFunc func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome
+
+func (fun ConditionFunc) Matches(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+	return fun(registry, env)
+}
+
+func OnProperty(name string, havingValue string) Condition {
+	return ConditionFunc(func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+		if env == nil || !env.ContainsProperty(name) {
+			return NewConditionOutcome(false, "OnProperty did not find property '"+name+"'")
+		}
+		value := strings.TrimSpace(fmt.Sprint(env.GetProperty(name, "")))
+		if havingValue == "" {
+			if strings.EqualFold(value, "false") {
+				return NewConditionOutcome(false, "OnProperty found property '"+name+"' but its value is false")
+			}
+			return NewConditionOutcome(true, "OnProperty found property '"+name+"'")
+		}
+		if !strings.EqualFold(value, havingValue) {
+			return NewConditionOutcome(false, "OnProperty found property '"+name+"' with different value '"+value+"', expected '"+havingValue+"'")
+		}
+		return NewConditionOutcome(true, "OnProperty found property '"+name+"' with value '"+value+"'")
+	})
+}
+
+func OnPea(typ goo.Type) Condition {
+	return ConditionFunc(func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+		peaNames := registry.GetPeaNamesByType(typ)
+		if len(peaNames) == 0 {
+			return NewConditionOutcome(false, "OnPea did not find any pea of type "+typ.GetFullName())
+		}
+		return NewConditionOutcome(true, "OnPea found peas of type "+typ.GetFullName()+" : "+strings.Join(peaNames, ", "))
+	})
+}
+
+func OnMissingPea(typ goo.Type) Condition {
+	return ConditionFunc(func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+		peaNames := registry.GetPeaNamesByType(typ)
+		if len(peaNames) != 0 {
+			return NewConditionOutcome(false, "OnMissingPea found peas of type "+typ.GetFullName()+" : "+strings.Join(peaNames, ", "))
+		}
+		return NewConditionOutcome(true, "OnMissingPea did not find any pea of type "+typ.GetFullName())
+	})
+}
+
+func OnType(typ goo.Type) Condition {
+	return ConditionFunc(func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+		componentTypes, err := core.GetComponentTypes(typ)
+		if err != nil || len(componentTypes) == 0 {
+			return NewConditionOutcome(false, "OnType did not find any registered component of type "+typ.GetFullName())
+		}
+		return NewConditionOutcome(true, "OnType found registered components of type "+typ.GetFullName())
+	})
+}
+
+func OnMissingType(typ goo.Type) Condition {
+	return ConditionFunc(func(registry peas.PeaDefinitionRegistry, env core.Environment) ConditionOutcome {
+		componentTypes, err := core.GetComponentTypes(typ)
+		if err == nil && len(componentTypes) != 0 {
+			return NewConditionOutcome(false, "OnMissingType found registered components of type "+typ.GetFullName())
+		}
+		return NewConditionOutcome(true, "OnMissingType did not find any registered component of type "+typ.GetFullName())
+	})
+}
+
+type ConditionEvaluation struct {
+	ComponentName string
+	Matched       bool
+	Message       string
+}
+
+type ConditionEvaluationReport struct {
+	evaluations []ConditionEvaluation
+	mu          sync.RWMutex
+}
+
+func NewConditionEvaluationReport() *ConditionEvaluationReport {
+	return &ConditionEvaluationReport{
+		evaluations: make([]ConditionEvaluation, 0),
+		mu:          sync.RWMutex{},
+	}
+}
+
+func (report *ConditionEvaluationReport) RecordEvaluation(componentName string, outcome ConditionOutcome) {
+	report.mu.Lock()
+	report.evaluations = append(report.evaluations, ConditionEvaluation{
+		ComponentName: componentName,
+		Matched:       outcome.IsMatched(),
+		Message:       outcome.GetMessage(),
+	})
+	report.mu.Unlock()
+}
+
+func (report *ConditionEvaluationReport) GetEvaluations() []ConditionEvaluation {
+	report.mu.RLock()
+	defer report.mu.RUnlock()
+	evaluations := make([]ConditionEvaluation, len(report.evaluations))
+	copy(evaluations, report.evaluations)
+	return evaluations
+}
+
+func (report *ConditionEvaluationReport) GetEvaluationsByComponent(componentName string) []ConditionEvaluation {
+	evaluations := make([]ConditionEvaluation, 0)
+	for _, evaluation := range report.GetEvaluations() {
+		if evaluation.ComponentName == componentName {
+			evaluations = append(evaluations, evaluation)
+		}
+	}
+	return evaluations
+}
+
+func (report *ConditionEvaluationReport) String() string {
+	positiveMatches := strings.Builder{}
+	negativeMatches := strings.Builder{}
+	for _, evaluation := range report.GetEvaluations() {
+		line := fmt.Sprintf("   %s :\n      - %s\n", evaluation.ComponentName, evaluation.Message)
+		if evaluation.Matched {
+			positiveMatches.WriteString(line)
+		} else {
+			negativeMatches.WriteString(line)
+		}
+	}
+	return "CONDITION EVALUATION REPORT\n\nPositive matches:\n" + positiveMatches.String() +
+		"\nNegative matches:\n" + negativeMatches.String()
+}
@@ -0,0 +1,129 @@
+package context
+
+import (
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testDataSource interface {
+	GetDataSourceName() string
+}
+
+type testUserDataSource struct {
+}
+
+func newTestUserDataSource() testUserDataSource {
+	return testUserDataSource{}
+}
+
+func (dataSource testUserDataSource) GetDataSourceName() string {
+	return "user"
+}
+
+type testDefaultDataSource struct {
+}
+
+func newTestDefaultDataSource() testDefaultDataSource {
+	return testDefaultDataSource{}
+}
+
+func (dataSource testDefaultDataSource) GetDataSourceName() string {
+	return "default"
+}
+
+func (dataSource testDefaultDataSource) GetConditions() []Condition {
+	return []Condition{
+		OnMissingPea(goo.GetType((*testDataSource)(nil))),
+	}
+}
+
+type testPropertyConditionalComponent struct {
+}
+
+func newTestPropertyConditionalComponent() testPropertyConditionalComponent {
+	return testPropertyConditionalComponent{}
+}
+
+func (component testPropertyConditionalComponent) GetConditions() []Condition {
+	return []Condition{
+		OnProperty("test.enabled", "true"),
+	}
+}
+
+func newTestConditionEnvironment(args ...string) core.StandardEnvironment {
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource(append([]string{"app"}, args...)))
+	return env
+}
+
+func TestOnProperty(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	env := newTestConditionEnvironment("--test.enabled=true", "--test.disabled=false", "--test.mode=fast")
+
+	assert.True(t, OnProperty("test.enabled", "").Matches(registry, env).IsMatched())
+	assert.False(t, OnProperty("test.disabled", "").Matches(registry, env).IsMatched())
+	assert.False(t, OnProperty("test.missing", "").Matches(registry, env).IsMatched())
+	assert.True(t, OnProperty("test.mode", "fast").Matches(registry, env).IsMatched())
+	assert.False(t, OnProperty("test.mode", "slow").Matches(registry, env).IsMatched())
+	assert.False(t, OnProperty("test.mode", "").Matches(registry, nil).IsMatched())
+}
+
+func TestOnPeaAndOnMissingPea(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+
+	assert.False(t, OnPea(dataSourceType).Matches(registry, nil).IsMatched())
+	assert.True(t, OnMissingPea(dataSourceType).Matches(registry, nil).IsMatched())
+
+	registry.RegisterPeaDefinition("testUserDataSource", peas.NewSimplePeaDefinition(goo.GetType(newTestUserDataSource)))
+	outcome := OnPea(dataSourceType).Matches(registry, nil)
+	assert.True(t, outcome.IsMatched())
+	assert.True(t, strings.Contains(outcome.GetMessage(), "testUserDataSource"))
+	assert.False(t, OnMissingPea(dataSourceType).Matches(registry, nil).IsMatched())
+}
+
+func TestOnTypeAndOnMissingType(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	converterType := goo.GetType((*core.TypeConverterService)(nil))
+	assert.True(t, OnType(converterType).Matches(registry, nil).IsMatched())
+	assert.False(t, OnMissingType(converterType).Matches(registry, nil).IsMatched())
+
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+	assert.False(t, OnType(dataSourceType).Matches(registry, nil).IsMatched())
+	assert.True(t, OnMissingType(dataSourceType).Matches(registry, nil).IsMatched())
+}
+
+func TestComponentPeaDefinitionScanner_ScanConditionalComponents(t *testing.T) {
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	scanner := NewComponentPeaDefinitionScanner(registry, newTestConditionEnvironment())
+	err := scanner.scanComponents(map[string]goo.Type{
+		"testDefaultDataSource":            goo.GetType(newTestDefaultDataSource),
+		"testUserDataSource":               goo.GetType(newTestUserDataSource),
+		"testPropertyConditionalComponent": goo.GetType(newTestPropertyConditionalComponent),
+	})
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("testUserDataSource"))
+	assert.False(t, registry.ContainsPeaDefinition("testDefaultDataSource"))
+	assert.False(t, registry.ContainsPeaDefinition("testPropertyConditionalComponent"))
+
+	report := scanner.GetConditionEvaluationReport()
+	assert.Equal(t, 2, len(report.GetEvaluations()))
+	evaluations := report.GetEvaluationsByComponent("testDefaultDataSource")
+	assert.Equal(t, 1, len(evaluations))
+	assert.False(t, evaluations[0].Matched)
+	assert.True(t, strings.Contains(report.String(), "Negative matches"))
+
+	registry = peas.NewDefaultPeaDefinitionRegistry()
+	scanner = NewComponentPeaDefinitionScanner(registry, newTestConditionEnvironment("--test.enabled=true"))
+	err = scanner.scanComponents(map[string]goo.Type{
+		"testDefaultDataSource":            goo.GetType(newTestDefaultDataSource),
+		"testPropertyConditionalComponent": goo.GetType(newTestPropertyConditionalComponent),
+	})
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("testDefaultDataSource"))
+	assert.True(t, registry.ContainsPeaDefinition("testPropertyConditionalComponent"))
+}
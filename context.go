@@ -1,15 +1,27 @@
 package context
 
 import (
+	"errors"
+	"fmt"
 	"github.com/procyon-projects/goo"
 	core "github.com/procyon-projects/procyon-core"
 	"github.com/procyon-projects/procyon-peas"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 const bootstrapProcessor = "github.com.procyon.context.bootstrapProcessor"
 const eventListenerProcessor = "github.com.procyon.context.eventListenerProcessor"
 
+const LazyInitializationProperty = "procyon.context.lazy-initialization"
+
+const startupSummaryStepCount = 10
+
 type ApplicationId string
 type ContextId string
 
@@ -25,15 +37,51 @@ type ApplicationContext interface {
 	GetAppId() ApplicationId
 	GetApplicationName() string
 	GetStartupTimestamp() int64
+	GetPeaByQualifier(qualifier string, typ goo.Type) (interface{}, error)
+	GetPrimaryPea(typ goo.Type) (interface{}, error)
+	GetPeasOfType(typ goo.Type) (map[string]interface{}, error)
+}
+
+type NoUniquePeaError struct {
+	typeName string
+	peaNames []string
+}
+
+func NewNoUniquePeaError(typeName string, peaNames []string) NoUniquePeaError {
+	return NoUniquePeaError{
+		typeName,
+		peaNames,
+	}
+}
+
+func (err NoUniquePeaError) GetTypeName() string {
+	return err.typeName
+}
+
+func (err NoUniquePeaError) GetPeaNames() []string {
+	return err.peaNames
+}
+
+func (err NoUniquePeaError) Error() string {
+	if len(err.peaNames) == 0 {
+		return "no pea found for the type " + err.typeName
+	}
+	return "expected single matching pea for the type " + err.typeName + " but found " +
+		strconv.Itoa(len(err.peaNames)) + " : " + strings.Join(err.peaNames, ", ")
 }
 
 type ConfigurableContext interface {
+	RegisterScope(scopeName peas.PeaScope, scope Scope)
+	GetRegisteredScope(scopeName peas.PeaScope) Scope
 	SetLogger(logger Logger)
 	GetLogger() Logger
 	SetEnvironment(environment core.ConfigurableEnvironment)
 	GetEnvironment() core.ConfigurableEnvironment
 	GetPeaFactory() peas.ConfigurablePeaFactory
 	AddApplicationListener(listener ApplicationListener)
+	RegisterDestructionCallback(peaName string, callback func() error)
+	Refresh() ([]string, error)
+	Close() error
 }
 
 type ConfigurableApplicationContext interface {
@@ -42,7 +90,7 @@ type ConfigurableApplicationContext interface {
 }
 
 type ConfigurableContextAdapter interface {
-	Configure()
+	Configure() error
 	OnConfigure()
 	FinishConfigure()
 }
@@ -59,6 +107,25 @@ type BaseApplicationContext struct {
 	mu                          *sync.RWMutex
 	applicationEventBroadcaster ApplicationEventBroadcaster
 	applicationListeners        []ApplicationListener
+	initializers                []ApplicationContextInitializer
+	scopes                      map[peas.PeaScope]Scope
+	muScopes                    *sync.RWMutex
+	excludedTypes               []goo.Type
+	componentFilters            *ComponentFilters
+	dependencyGraph             *DependencyGraph
+	failureAnalyzers            []FailureAnalyzer
+	muFailureAnalyzers          *sync.RWMutex
+	startupRecorder             *StartupRecorder
+	applicationArguments        *ApplicationArguments
+	createdPeaNames             []string
+	destructionCallbacks        map[string][]func() error
+	destructionCallbackNames    []string
+	muDestruction               *sync.Mutex
+	propertySnapshot            map[string]string
+	propertiesHolders           map[string]*PropertiesHolder
+	environmentWatchers         []func()
+	muRefresh                   *sync.Mutex
+	closed                      bool
 	bag                         map[string]interface{}
 }
 
@@ -73,6 +140,21 @@ func NewBaseApplicationContext(appId ApplicationId, contextId ContextId, configu
 		ConfigurableContextAdapter: configurableContextAdapter,
 		ConfigurablePeaFactory:     peas.NewDefaultPeaFactory(),
 		applicationListeners:       make([]ApplicationListener, 0),
+		initializers:               make([]ApplicationContextInitializer, 0),
+		scopes:                     make(map[peas.PeaScope]Scope, 0),
+		muScopes:                   &sync.RWMutex{},
+		excludedTypes:              make([]goo.Type, 0),
+		componentFilters:           NewComponentFilters(),
+		failureAnalyzers:           make([]FailureAnalyzer, 0),
+		muFailureAnalyzers:         &sync.RWMutex{},
+		startupRecorder:            NewStartupRecorder(),
+		createdPeaNames:            make([]string, 0),
+		destructionCallbacks:       make(map[string][]func() error, 0),
+		destructionCallbackNames:   make([]string, 0),
+		muDestruction:              &sync.Mutex{},
+		propertiesHolders:          make(map[string]*PropertiesHolder, 0),
+		environmentWatchers:        make([]func(), 0),
+		muRefresh:                  &sync.Mutex{},
 		bag:                        make(map[string]interface{}, 0),
 	}
 	ctx.initContext()
@@ -152,29 +234,585 @@ func (ctx *BaseApplicationContext) GetApplicationListeners() []ApplicationListen
 	return ctx.applicationListeners
 }
 
+func (ctx *BaseApplicationContext) AddInitializer(initializer ApplicationContextInitializer) {
+	if initializer == nil {
+		panic("Initializer must not be null")
+	}
+	ctx.initializers = append(ctx.initializers, initializer)
+}
+
+func (ctx *BaseApplicationContext) GetInitializers() []ApplicationContextInitializer {
+	return ctx.initializers
+}
+
+func (ctx *BaseApplicationContext) RegisterScope(scopeName peas.PeaScope, scope Scope) {
+	if scopeName == "" || scope == nil {
+		panic("Scope name or scope must not be null or empty")
+	}
+	if scopeName == peas.SharedScope || scopeName == peas.PrototypeScope || scopeName == RequestScope {
+		panic("You cannot replace the built-in scope : " + string(scopeName))
+	}
+	ctx.muScopes.Lock()
+	ctx.scopes[scopeName] = scope
+	ctx.muScopes.Unlock()
+}
+
+func (ctx *BaseApplicationContext) GetRegisteredScope(scopeName peas.PeaScope) Scope {
+	ctx.muScopes.RLock()
+	defer ctx.muScopes.RUnlock()
+	return ctx.scopes[scopeName]
+}
+
+func (ctx *BaseApplicationContext) NewRequestContext(contextId ContextId) *RequestContext {
+	return newRequestContext(ctx, contextId)
+}
+
+func (ctx *BaseApplicationContext) GetPea(name string) (interface{}, error) {
+	return ctx.getPea(name, nil, nil)
+}
+
+func (ctx *BaseApplicationContext) GetPeaByNameAndType(name string, typ goo.Type) (interface{}, error) {
+	return ctx.getPea(name, typ, nil)
+}
+
+func (ctx *BaseApplicationContext) GetAliases(peaName string) []string {
+	if peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry); ok {
+		if scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(peaName).(ScannedPeaDefinition); ok {
+			return scannedPeaDefinition.GetAliases()
+		}
+	}
+	return []string{}
+}
+
+func (ctx *BaseApplicationContext) resolvePeaName(name string) string {
+	peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if !ok || peaDefinitionRegistry.ContainsPeaDefinition(name) || ctx.GetPeaFactory().ContainsSharedPea(name) {
+		return name
+	}
+	if peaName, peaDefinition := findPeaDefinitionByAlias(peaDefinitionRegistry, name); peaDefinition != nil {
+		return peaName
+	}
+	return name
+}
+
+func (ctx *BaseApplicationContext) GetPeaByType(typ goo.Type) (interface{}, error) {
+	if typ == nil {
+		return nil, errors.New("type must not be null")
+	}
+	if _, err := ctx.getPeaDefinitionRegistry(); err != nil {
+		return ctx.GetPeaFactory().GetPeaByType(typ)
+	}
+	peaNames, err := ctx.getPeaNamesByType(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(peaNames) > 1 {
+		return ctx.GetPrimaryPea(typ)
+	} else if len(peaNames) == 1 {
+		return ctx.getPea(peaNames[0], typ, nil)
+	}
+	return ctx.GetPeaFactory().GetPeaByType(typ)
+}
+
+func (ctx *BaseApplicationContext) GetPeaByQualifier(qualifier string, typ goo.Type) (interface{}, error) {
+	if typ == nil {
+		return nil, errors.New("type must not be null")
+	}
+	peaDefinitionRegistry, err := ctx.getPeaDefinitionRegistry()
+	if err != nil {
+		return nil, err
+	}
+	peaNames, err := ctx.getPeaNamesByType(typ)
+	if err != nil {
+		return nil, err
+	}
+	candidateNames := make([]string, 0)
+	for _, peaName := range peaNames {
+		scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(peaName).(ScannedPeaDefinition)
+		if peaName == qualifier || (ok && scannedPeaDefinition.HasQualifier(qualifier)) {
+			candidateNames = append(candidateNames, peaName)
+		}
+	}
+	if len(candidateNames) != 1 {
+		return nil, NewNoUniquePeaError(typ.GetFullName()+" qualified by '"+qualifier+"'", candidateNames)
+	}
+	return ctx.getPea(candidateNames[0], typ, nil)
+}
+
+func (ctx *BaseApplicationContext) GetPrimaryPea(typ goo.Type) (interface{}, error) {
+	if typ == nil {
+		return nil, errors.New("type must not be null")
+	}
+	peaDefinitionRegistry, err := ctx.getPeaDefinitionRegistry()
+	if err != nil {
+		return nil, err
+	}
+	peaNames, err := ctx.getPeaNamesByType(typ)
+	if err != nil {
+		return nil, err
+	}
+	if len(peaNames) == 1 {
+		return ctx.getPea(peaNames[0], typ, nil)
+	}
+	primaryPeaNames := getPrimaryPeaNames(peaDefinitionRegistry, peaNames)
+	if len(primaryPeaNames) != 1 {
+		return nil, NewNoUniquePeaError(typ.GetFullName(), peaNames)
+	}
+	return ctx.getPea(primaryPeaNames[0], typ, nil)
+}
+
+func getPrimaryPeaNames(peaDefinitionRegistry peas.PeaDefinitionRegistry, peaNames []string) []string {
+	primaryPeaNames := make([]string, 0)
+	for _, peaName := range peaNames {
+		if scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(peaName).(ScannedPeaDefinition); ok && scannedPeaDefinition.IsPrimary() {
+			primaryPeaNames = append(primaryPeaNames, peaName)
+		}
+	}
+	return primaryPeaNames
+}
+
+func (ctx *BaseApplicationContext) GetPeasOfType(typ goo.Type) (map[string]interface{}, error) {
+	if typ == nil {
+		return nil, errors.New("type must not be null")
+	}
+	peaDefinitionRegistry, err := ctx.getPeaDefinitionRegistry()
+	if err != nil {
+		return nil, err
+	}
+	peaNames, err := ctx.getPeaNamesByType(typ)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{}, 0)
+	for _, peaName := range peaNames {
+		if scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(peaName).(ScannedPeaDefinition); ok && scannedPeaDefinition.GetComponentScope() == RequestScope {
+			continue
+		}
+		instance, err := ctx.getPea(peaName, typ, nil)
+		if err != nil {
+			return nil, err
+		}
+		result[peaName] = instance
+	}
+	return result, nil
+}
+
+func (ctx *BaseApplicationContext) getPeaDefinitionRegistry() (peas.PeaDefinitionRegistry, error) {
+	peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if !ok {
+		return nil, errors.New("pea factory does not support the pea definition registry")
+	}
+	return peaDefinitionRegistry, nil
+}
+
+func (ctx *BaseApplicationContext) getPeaNamesByType(typ goo.Type) ([]string, error) {
+	peaFactory := ctx.GetPeaFactory()
+	peaDefinitionRegistry, err := ctx.getPeaDefinitionRegistry()
+	if err != nil {
+		return nil, err
+	}
+	peaNames := peaDefinitionRegistry.GetPeaNamesByType(typ)
+	for _, sharedPeaName := range peaFactory.GetSharedPeaNames() {
+		if peaDefinitionRegistry.ContainsPeaDefinition(sharedPeaName) {
+			continue
+		}
+		if isAssignableType(goo.GetType(peaFactory.GetSharedPea(sharedPeaName)), typ) {
+			peaNames = append(peaNames, sharedPeaName)
+		}
+	}
+	sort.Strings(peaNames)
+	return peaNames, nil
+}
+
+func isAssignableType(peaType goo.Type, requiredType goo.Type) bool {
+	if peaType.IsFunction() {
+		peaType = peaType.ToFunctionType().GetFunctionReturnTypes()[0]
+	}
+	if peaType.GetGoType() == requiredType.GetGoType() {
+		return true
+	} else if requiredType.IsInterface() && peaType.IsStruct() {
+		return peaType.ToStructType().Implements(requiredType.ToInterfaceType())
+	} else if requiredType.IsStruct() && peaType.IsStruct() {
+		return peaType.ToStructType().EmbeddedStruct(requiredType.ToStructType())
+	}
+	return false
+}
+
+func (ctx *BaseApplicationContext) getPea(name string, typ goo.Type, requestScope Scope) (interface{}, error) {
+	name = ctx.resolvePeaName(name)
+	scope, err := ctx.getPeaScope(name, requestScope)
+	if err != nil {
+		return nil, err
+	}
+	peaFactory := ctx.GetPeaFactory()
+	objFunc := func() (interface{}, error) {
+		arguments, err := ctx.resolveConstructorArguments(name)
+		if err != nil {
+			return nil, err
+		} else if arguments != nil {
+			return ctx.createPeaWithArguments(name, typ, arguments)
+		} else if typ == nil {
+			return peaFactory.GetPea(name)
+		}
+		return peaFactory.GetPeaByNameAndType(name, typ)
+	}
+	if scope == nil {
+		return objFunc()
+	}
+	created := false
+	instance, err := scope.Get(name, func() (interface{}, error) {
+		created = true
+		return objFunc()
+	})
+	if err == nil && created {
+		if disposable, ok := instance.(Disposable); ok {
+			scope.RegisterDestructionCallback(name, func() {
+				ctx.destroyPea(name, disposable.Destroy)
+			})
+		}
+	}
+	return instance, err
+}
+
+func (ctx *BaseApplicationContext) resolveConstructorArguments(name string) ([]interface{}, error) {
+	peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if !ok || ctx.GetPeaFactory().ContainsSharedPea(name) {
+		return nil, nil
+	}
+	/* the pea factory calls the constructors of the shared peas only, the prototypes are created as zero values */
+	peaDefinition := peaDefinitionRegistry.GetPeaDefinition(name)
+	if peaDefinition == nil || peaDefinition.GetScope() != peas.SharedScope || !peaDefinition.GetPeaType().IsFunction() {
+		return nil, nil
+	}
+	parameterTypes := peaDefinition.GetPeaType().ToFunctionType().GetFunctionParameterTypes()
+	if len(parameterTypes) == 0 {
+		return nil, nil
+	}
+	arguments := make([]interface{}, len(parameterTypes))
+	for index, parameterType := range parameterTypes {
+		dependencyName, candidateNames := resolveDependencyName(ctx.GetPeaFactory(), peaDefinitionRegistry, name, parameterType)
+		if len(candidateNames) == 0 {
+			continue
+		} else if dependencyName == "" {
+			return nil, NewNoUniquePeaError(parameterType.GetFullName()+" required by '"+name+"'", candidateNames)
+		}
+		/* a shared pea would keep the instance of the first request for all requests */
+		if scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(dependencyName).(ScannedPeaDefinition); ok && scannedPeaDefinition.GetComponentScope() == RequestScope {
+			return nil, errors.New("shared pea '" + name + "' cannot depend on the request scoped pea '" + dependencyName + "'")
+		}
+		instance, err := ctx.getPea(dependencyName, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		arguments[index] = getConstructorArgument(instance, parameterType)
+	}
+	return arguments, nil
+}
+
+func getConstructorArgument(instance interface{}, parameterType goo.Type) interface{} {
+	if instance == nil || parameterType.IsPointer() || parameterType.IsInterface() {
+		return instance
+	}
+	/* the parameters which are not pointers get a copy like the pea factory does */
+	if value := reflect.ValueOf(instance); value.Kind() == reflect.Ptr {
+		return value.Elem().Interface()
+	}
+	return instance
+}
+
+func (ctx *BaseApplicationContext) createPeaWithArguments(name string, typ goo.Type, arguments []interface{}) (interface{}, error) {
+	peaFactory := ctx.GetPeaFactory()
+	if typ != nil {
+		peaDefinition := peaFactory.(peas.PeaDefinitionRegistry).GetPeaDefinition(name)
+		if !isAssignableType(peaDefinition.GetPeaType(), typ) {
+			return nil, errors.New("pea definition type does not match the required type")
+		}
+	}
+	return peaFactory.GetPeaByNameAndArgs(name, arguments...)
+}
+
+func (ctx *BaseApplicationContext) getPeaScope(name string, requestScope Scope) (Scope, error) {
+	peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if !ok {
+		return nil, nil
+	}
+	scannedPeaDefinition, ok := peaDefinitionRegistry.GetPeaDefinition(name).(ScannedPeaDefinition)
+	if !ok {
+		return nil, nil
+	}
+	scopeName := scannedPeaDefinition.GetComponentScope()
+	switch scopeName {
+	case peas.SharedScope, peas.PrototypeScope:
+		return nil, nil
+	case RequestScope:
+		if requestScope == nil {
+			return nil, errors.New("request scoped pea must be obtained from a request context : " + name)
+		}
+		return requestScope, nil
+	}
+	scope := ctx.GetRegisteredScope(scopeName)
+	if scope == nil {
+		return nil, errors.New("there is no registered scope named " + string(scopeName) + " for the pea : " + name)
+	}
+	return scope, nil
+}
+
+func (ctx *BaseApplicationContext) GetComponentFilters() *ComponentFilters {
+	return ctx.componentFilters
+}
+
+func (ctx *BaseApplicationContext) GetConditionEvaluationReport() *ConditionEvaluationReport {
+	if report, ok := ctx.GetPeaFactory().GetSharedPea(conditionEvaluationReport).(*ConditionEvaluationReport); ok {
+		return report
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) ExcludeType(typ goo.Type) error {
+	err := ctx.GetPeaFactory().ExcludeType(typ)
+	if err != nil {
+		return err
+	}
+	ctx.excludedTypes = append(ctx.excludedTypes, typ)
+	return nil
+}
+
 func (ctx *BaseApplicationContext) PublishEvent(event ApplicationEvent) {
 	ctx.applicationEventBroadcaster.BroadcastEvent(ctx, event)
 }
 
-func (ctx *BaseApplicationContext) Configure() {
+func (ctx *BaseApplicationContext) AddFailureAnalyzer(analyzer FailureAnalyzer) {
+	if analyzer == nil {
+		panic("Failure analyzer must not be null")
+	}
+	ctx.muFailureAnalyzers.Lock()
+	ctx.failureAnalyzers = append(ctx.failureAnalyzers, analyzer)
+	ctx.muFailureAnalyzers.Unlock()
+}
+
+func (ctx *BaseApplicationContext) GetFailureAnalyzers() []FailureAnalyzer {
+	ctx.muFailureAnalyzers.RLock()
+	defer ctx.muFailureAnalyzers.RUnlock()
+	return append(append([]FailureAnalyzer{}, ctx.failureAnalyzers...), getDefaultFailureAnalyzers()...)
+}
+
+func (ctx *BaseApplicationContext) Configure() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+		if err != nil {
+			if ctx.applicationEventBroadcaster != nil {
+				ctx.PublishEvent(NewApplicationFailedEvent(ctx, err))
+			}
+			ctx.reportFailure(err)
+		}
+	}()
+	err = ctx.configure()
+	if err != nil {
+		return
+	}
+	/* application runners are called without holding the lock, they might use the context or run as long as the application */
+	arguments, err := ctx.GetApplicationArguments()
+	if err != nil {
+		return
+	}
+	err = ctx.callRunners(arguments)
+	return
+}
+
+func (ctx *BaseApplicationContext) configure() (err error) {
 	ctx.mu.Lock()
-	err := ctx.preparePeaFactory()
+	defer ctx.mu.Unlock()
+	ctx.startupTimestamp = time.Now().Unix()
+	configureStep := ctx.startupRecorder.Start("context.configure")
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+		configureStep.End()
+		if err == nil && ctx.logger != nil {
+			ctx.logger.Infof(ctx, "Application context configured in %s", configureStep.GetDuration().String())
+			ctx.logger.Debug(ctx, ctx.startupRecorder.Summary(startupSummaryStepCount))
+		}
+	}()
+	/* context initializers */
+	step := ctx.startupRecorder.Start("context.initializers")
+	err = ctx.invokeInitializers()
+	step.End()
 	if err != nil {
-		panic(err)
+		return
+	}
+	step = ctx.startupRecorder.Start("context.pea-factory.prepare")
+	err = ctx.preparePeaFactory()
+	step.End()
+	if err != nil {
+		return
 	}
 	/* pea processors */
-	ctx.initPeaProcessors()
+	err = ctx.initPeaProcessors()
+	if err != nil {
+		return
+	}
+	if report := ctx.GetConditionEvaluationReport(); report != nil && ctx.logger != nil {
+		ctx.logger.Debug(ctx, report.String())
+	}
 	/* application event broadcaster */
 	ctx.initApplicationEventBroadcaster()
 	/* custom configure */
+	step = ctx.startupRecorder.Start("context.on-configure")
 	ctx.OnConfigure()
+	step.End()
 	/* application event listeners */
+	step = ctx.startupRecorder.Start("context.event-listeners.register")
 	ctx.initApplicationEventListeners()
+	step.End()
+	/* dependency graph */
+	step = ctx.startupRecorder.Start("context.dependency-graph.check")
+	err = ctx.checkDependencyGraph()
+	step.End()
+	if err != nil {
+		return
+	}
 	/* finish pea factory initialization */
-	ctx.finishPeaFactoryInitialization()
+	step = ctx.startupRecorder.Start("context.peas.pre-instantiate")
+	err = ctx.finishPeaFactoryInitialization()
+	step.End()
+	if err != nil {
+		return
+	}
 	/* finish the configure */
+	step = ctx.startupRecorder.Start("context.finish-configure")
 	ctx.FinishConfigure()
-	ctx.mu.Unlock()
+	step.End()
+	/* the changes of the environment are detected by comparing it with this snapshot */
+	ctx.muRefresh.Lock()
+	ctx.propertySnapshot = ctx.takePropertySnapshot()
+	ctx.muRefresh.Unlock()
+	return
+}
+
+func (ctx *BaseApplicationContext) SetApplicationArguments(arguments ApplicationArguments) {
+	ctx.applicationArguments = &arguments
+}
+
+func (ctx *BaseApplicationContext) GetApplicationArguments() (ApplicationArguments, error) {
+	if ctx.applicationArguments != nil {
+		return *ctx.applicationArguments, nil
+	}
+	arguments, err := NewApplicationArguments(os.Args[1:])
+	if err != nil {
+		return arguments, err
+	}
+	ctx.applicationArguments = &arguments
+	return arguments, nil
+}
+
+func (ctx *BaseApplicationContext) getRunners() ([]interface{}, error) {
+	runners := make([]interface{}, 0)
+	/* runners are looked up by their types, which requires a pea definition registry */
+	if _, err := ctx.getPeaDefinitionRegistry(); err != nil {
+		return runners, nil
+	}
+	runnerTypes := []goo.Type{
+		goo.GetType((*ApplicationRunner)(nil)),
+		goo.GetType((*CommandLineRunner)(nil)),
+	}
+	for _, runnerType := range runnerTypes {
+		runnerNames, err := ctx.getPeaNamesByType(runnerType)
+		if err != nil {
+			return nil, err
+		}
+		for _, runnerName := range runnerNames {
+			runner, err := ctx.GetPea(runnerName)
+			if err != nil {
+				return nil, err
+			}
+			if runner != nil {
+				runners = append(runners, runner)
+			}
+		}
+	}
+	sortByOrder(runners)
+	return runners, nil
+}
+
+func (ctx *BaseApplicationContext) callRunners(arguments ApplicationArguments) error {
+	runners, err := ctx.getRunners()
+	if err != nil {
+		return err
+	}
+	for _, runner := range runners {
+		step := ctx.startupRecorder.Start("context.runner.run").
+			Tag("runner", goo.GetType(runner).GetFullName())
+		switch typedRunner := runner.(type) {
+		case ApplicationRunner:
+			err = typedRunner.Run(arguments)
+		case CommandLineRunner:
+			err = typedRunner.Run(arguments.GetSourceArgs()...)
+		}
+		step.End()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) GetStartupRecorder() *StartupRecorder {
+	return ctx.startupRecorder
+}
+
+func (ctx *BaseApplicationContext) MustConfigure() {
+	err := ctx.Configure()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (ctx *BaseApplicationContext) reportFailure(err error) {
+	if ctx.logger == nil {
+		return
+	}
+	analysis := analyzeFailure(ctx.GetFailureAnalyzers(), err)
+	if analysis != nil {
+		ctx.logger.Error(ctx, analysis.String())
+		return
+	}
+	ctx.logger.Errorf(ctx, "Application context could not be configured : %s", err.Error())
+}
+
+func (ctx *BaseApplicationContext) checkDependencyGraph() error {
+	ctx.dependencyGraph = newDependencyGraph(ctx.GetPeaFactory())
+	if ctx.logger != nil {
+		for _, missingDependency := range ctx.dependencyGraph.GetMissingDependencies() {
+			ctx.logger.Warningf(ctx, "No pea found for the dependency '%s' of the pea '%s'", missingDependency.TypeName, missingDependency.PeaName)
+		}
+		for _, ambiguousDependency := range ctx.dependencyGraph.GetAmbiguousDependencies() {
+			ctx.logger.Warningf(ctx, "More than one pea found for the dependency '%s' of the pea '%s' : %s", ambiguousDependency.TypeName,
+				ambiguousDependency.PeaName, strings.Join(ambiguousDependency.Candidates, ", "))
+		}
+	}
+	return ctx.dependencyGraph.CheckCycles()
+}
+
+func (ctx *BaseApplicationContext) GetDependencyGraph() *DependencyGraph {
+	if ctx.dependencyGraph == nil {
+		return newDependencyGraph(ctx.GetPeaFactory())
+	}
+	return ctx.dependencyGraph
+}
+
+func (ctx *BaseApplicationContext) invokeInitializers() error {
+	componentInitializers, err := getComponentInitializers()
+	if err != nil {
+		return err
+	}
+	initializers := append(componentInitializers, ctx.initializers...)
+	for _, initializer := range sortInitializers(initializers) {
+		initializer.InitializeContext(ctx)
+	}
+	return nil
 }
 
 func (ctx *BaseApplicationContext) preparePeaFactory() (err error) {
@@ -187,87 +825,124 @@ func (ctx *BaseApplicationContext) preparePeaFactory() (err error) {
 	if err != nil {
 		return err
 	}
+	err = peaFactory.RegisterSharedPea(componentScanFilters, ctx.componentFilters)
+	if err != nil {
+		return err
+	}
+	arguments, err := ctx.GetApplicationArguments()
+	if err != nil {
+		return err
+	}
+	err = peaFactory.RegisterSharedPea(applicationArguments, arguments)
+	if err != nil {
+		return err
+	}
 	peaFactory.RegisterTypeAsOnlyReadable(goo.GetType((*ConfigurationProperties)(nil)))
+	peaFactory.AddPeaProcessor(newAwarePeaProcessor(ctx))
+	peaFactory.AddPeaProcessor(newDestructionTrackingPeaProcessor(ctx))
 	return
 }
 
-func (ctx *BaseApplicationContext) initPeaProcessors() {
+func (ctx *BaseApplicationContext) initPeaProcessors() error {
 	peaFactory := ctx.GetPeaFactory()
 	if peaDefinitionRegistry, ok := peaFactory.(peas.PeaDefinitionRegistry); ok {
 		// pea definition registry processor
-		ctx.invokePeaDefinitionRegistryProcessors(ctx.getPeaDefinitionRegistryProcessors(peaDefinitionRegistry), peaDefinitionRegistry)
+		err := ctx.invokePeaDefinitionRegistryProcessors(peaDefinitionRegistry)
+		if err != nil {
+			return err
+		}
 		// pea factory processor
-		ctx.invokePeaFactoryProcessors(ctx.getPeaFactoryProcessors(peaDefinitionRegistry), peaFactory)
+		factoryProcessors, err := ctx.getPeaFactoryProcessors(peaDefinitionRegistry)
+		if err != nil {
+			return err
+		}
+		ctx.invokePeaFactoryProcessors(factoryProcessors, peaFactory)
 		// pea processors
-		ctx.registerPeaProcessors(peaDefinitionRegistry)
+		step := ctx.startupRecorder.Start("context.pea-processors.register")
+		err = ctx.registerPeaProcessors(peaDefinitionRegistry)
+		step.End()
+		return err
 	}
+	return nil
 }
 
-func (ctx *BaseApplicationContext) getPeaDefinitionRegistryProcessors(peaDefinitionRegistry peas.PeaDefinitionRegistry) []peas.PeaDefinitionRegistryProcessor {
-	processors := make([]peas.PeaDefinitionRegistryProcessor, 0)
-	peaFactory := ctx.GetPeaFactory()
-	processorType := goo.GetType((*peas.PeaDefinitionRegistryProcessor)(nil))
-	processorNames := peaDefinitionRegistry.GetPeaNamesByType(processorType)
+func (ctx *BaseApplicationContext) getOrderedProcessors(processorNames []string, processorType goo.Type) ([]interface{}, error) {
+	processors := make([]interface{}, 0)
+	sort.Strings(processorNames)
 	for _, processorName := range processorNames {
-		instance, err := peaFactory.GetPeaByNameAndType(processorName, processorType)
+		instance, err := ctx.getPea(processorName, processorType, nil)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		if instance != nil {
-			processors = append(processors, instance.(peas.PeaDefinitionRegistryProcessor))
+			processors = append(processors, instance)
 		}
 	}
-	return processors
+	sortByOrder(processors)
+	return processors, nil
 }
 
-func (ctx *BaseApplicationContext) invokePeaDefinitionRegistryProcessors(processors []peas.PeaDefinitionRegistryProcessor,
-	registry peas.PeaDefinitionRegistry) {
-	for _, processor := range processors {
-		processor.AfterPeaDefinitionRegistryInitialization(registry)
+func (ctx *BaseApplicationContext) invokePeaDefinitionRegistryProcessors(registry peas.PeaDefinitionRegistry) error {
+	processorType := goo.GetType((*peas.PeaDefinitionRegistryProcessor)(nil))
+	processedNames := make(map[string]bool, 0)
+	for {
+		/* registry processors might register new registry processors, they are invoked in the next round */
+		processorNames := make([]string, 0)
+		for _, processorName := range registry.GetPeaNamesByType(processorType) {
+			if !processedNames[processorName] {
+				processorNames = append(processorNames, processorName)
+				processedNames[processorName] = true
+			}
+		}
+		if len(processorNames) == 0 {
+			return nil
+		}
+		processors, err := ctx.getOrderedProcessors(processorNames, processorType)
+		if err != nil {
+			return err
+		}
+		for _, processor := range processors {
+			step := ctx.startupRecorder.Start("context.pea-definition-registry.process").
+				Tag("processor", goo.GetType(processor).GetFullName())
+			processor.(peas.PeaDefinitionRegistryProcessor).AfterPeaDefinitionRegistryInitialization(registry)
+			step.End()
+		}
 	}
 }
 
-func (ctx *BaseApplicationContext) getPeaFactoryProcessors(peaDefinitionRegistry peas.PeaDefinitionRegistry) []peas.PeaFactoryProcessor {
-	processors := make([]peas.PeaFactoryProcessor, 0)
-	peaFactory := ctx.GetPeaFactory()
+func (ctx *BaseApplicationContext) getPeaFactoryProcessors(peaDefinitionRegistry peas.PeaDefinitionRegistry) ([]peas.PeaFactoryProcessor, error) {
 	processorType := goo.GetType((*peas.PeaFactoryProcessor)(nil))
-	processorNames := peaDefinitionRegistry.GetPeaNamesByType(processorType)
-	for _, processorName := range processorNames {
-		instance, err := peaFactory.GetPeaByNameAndType(processorName, processorType)
-		if err != nil {
-			panic(err)
-		}
-		if instance != nil {
-			processors = append(processors, instance.(peas.PeaFactoryProcessor))
-		}
+	instances, err := ctx.getOrderedProcessors(peaDefinitionRegistry.GetPeaNamesByType(processorType), processorType)
+	if err != nil {
+		return nil, err
 	}
-	return processors
+	processors := make([]peas.PeaFactoryProcessor, 0)
+	for _, instance := range instances {
+		processors = append(processors, instance.(peas.PeaFactoryProcessor))
+	}
+	return processors, nil
 }
 
 func (ctx *BaseApplicationContext) invokePeaFactoryProcessors(processors []peas.PeaFactoryProcessor,
 	factory peas.ConfigurablePeaFactory) {
 	for _, processor := range processors {
+		step := ctx.startupRecorder.Start("context.pea-factory.process").
+			Tag("processor", goo.GetType(processor).GetFullName())
 		processor.AfterPeaFactoryInitialization(factory)
+		step.End()
 	}
 }
 
-func (ctx *BaseApplicationContext) registerPeaProcessors(peaDefinitionRegistry peas.PeaDefinitionRegistry) {
-	processors := make([]peas.PeaProcessor, 0)
-	peaFactory := ctx.GetPeaFactory()
+func (ctx *BaseApplicationContext) registerPeaProcessors(peaDefinitionRegistry peas.PeaDefinitionRegistry) error {
 	processorType := goo.GetType((*peas.PeaProcessor)(nil))
-	processorNames := peaDefinitionRegistry.GetPeaNamesByType(processorType)
-	for _, processorName := range processorNames {
-		instance, err := peaFactory.GetPeaByNameAndType(processorName, processorType)
-		if err != nil {
-			panic(err)
-		}
-		if instance != nil {
-			processors = append(processors, instance.(peas.PeaProcessor))
-		}
+	processors, err := ctx.getOrderedProcessors(peaDefinitionRegistry.GetPeaNamesByType(processorType), processorType)
+	if err != nil {
+		return err
 	}
 	for _, processor := range processors {
-		ctx.AddPeaProcessor(processor)
+		ctx.AddPeaProcessor(processor.(peas.PeaProcessor))
 	}
+	return nil
 }
 
 func (ctx *BaseApplicationContext) initApplicationEventBroadcaster() {
@@ -281,8 +956,187 @@ func (ctx *BaseApplicationContext) initApplicationEventListeners() {
 	}
 }
 
-func (ctx *BaseApplicationContext) finishPeaFactoryInitialization() {
-	ctx.GetPeaFactory().PreInstantiateSharedPeas()
+func (ctx *BaseApplicationContext) finishPeaFactoryInitialization() error {
+	peaDefinitionRegistry, ok := ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if !ok {
+		ctx.GetPeaFactory().PreInstantiateSharedPeas()
+		return nil
+	}
+	lazyInit := ctx.isLazyInitialization()
+	for _, peaName := range peaDefinitionRegistry.GetPeaDefinitionNames() {
+		peaDefinition := peaDefinitionRegistry.GetPeaDefinition(peaName)
+		if peaDefinition == nil || peaDefinition.GetScope() != peas.SharedScope || ctx.isExcludedType(peaName, peaDefinition.GetPeaType()) {
+			continue
+		}
+		if ctx.isLazyInitPea(peaDefinition, lazyInit) || ctx.ContainsSharedPea(peaName) {
+			continue
+		}
+		step := ctx.startupRecorder.Start("context.pea.instantiate").
+			Tag("peaName", peaName).
+			Tag("peaType", getComponentReturnType(peaDefinition.GetPeaType()).GetFullName())
+		_, err := ctx.GetPea(peaName)
+		step.End()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) isLazyInitialization() bool {
+	if ctx.environment == nil {
+		return false
+	}
+	value := ctx.environment.GetProperty(LazyInitializationProperty, "false")
+	if value == nil {
+		return false
+	}
+	lazyInit, err := strconv.ParseBool(strings.TrimSpace(fmt.Sprint(value)))
+	if err != nil {
+		return false
+	}
+	return lazyInit
+}
+
+func (ctx *BaseApplicationContext) isLazyInitPea(peaDefinition peas.PeaDefinition, defaultLazyInit bool) bool {
+	if scannedPeaDefinition, ok := peaDefinition.(ScannedPeaDefinition); ok {
+		return scannedPeaDefinition.IsLazyInit(defaultLazyInit)
+	}
+	return defaultLazyInit
+}
+
+func (ctx *BaseApplicationContext) getUninitializedPeaNames() []string {
+	peaNames := make([]string, 0)
+	peaFactory := ctx.GetPeaFactory()
+	peaDefinitionRegistry, ok := peaFactory.(peas.PeaDefinitionRegistry)
+	if !ok {
+		return peaNames
+	}
+	for _, peaName := range peaDefinitionRegistry.GetPeaDefinitionNames() {
+		peaDefinition := peaDefinitionRegistry.GetPeaDefinition(peaName)
+		if peaDefinition == nil || peaDefinition.GetScope() != peas.SharedScope {
+			continue
+		}
+		if !peaFactory.ContainsSharedPea(peaName) {
+			peaNames = append(peaNames, peaName)
+		}
+	}
+	sort.Strings(peaNames)
+	return peaNames
+}
+
+func (ctx *BaseApplicationContext) RegisterDestructionCallback(peaName string, callback func() error) {
+	if peaName == "" || callback == nil {
+		panic("Pea name or destruction callback must not be null or empty")
+	}
+	ctx.muDestruction.Lock()
+	if _, ok := ctx.destructionCallbacks[peaName]; !ok {
+		ctx.destructionCallbackNames = append(ctx.destructionCallbackNames, peaName)
+	}
+	ctx.destructionCallbacks[peaName] = append(ctx.destructionCallbacks[peaName], callback)
+	ctx.muDestruction.Unlock()
+}
+
+func (ctx *BaseApplicationContext) registerCreatedPea(peaName string) {
+	ctx.muDestruction.Lock()
+	ctx.createdPeaNames = append(ctx.createdPeaNames, peaName)
+	ctx.muDestruction.Unlock()
+}
+
+func (ctx *BaseApplicationContext) Close() error {
+	ctx.mu.Lock()
+	if ctx.closed {
+		ctx.mu.Unlock()
+		return nil
+	}
+	ctx.closed = true
+	ctx.mu.Unlock()
+	ctx.stopWatchingEnvironment()
+	/* the listeners and the destruction callbacks are invoked without holding the lock, they might use the context */
+	if ctx.applicationEventBroadcaster != nil {
+		ctx.PublishEvent(NewApplicationContextClosedEvent(ctx))
+	}
+	uninitializedPeaNames := ctx.getUninitializedPeaNames()
+	if len(uninitializedPeaNames) != 0 && ctx.logger != nil {
+		ctx.logger.Warningf(ctx, "Peas which have never been initialized : %s", strings.Join(uninitializedPeaNames, ", "))
+	}
+	return ctx.destroyPeas()
+}
+
+func (ctx *BaseApplicationContext) destroyPeas() error {
+	ctx.muDestruction.Lock()
+	createdPeaNames := ctx.createdPeaNames
+	callbacks := ctx.destructionCallbacks
+	callbackNames := ctx.destructionCallbackNames
+	ctx.createdPeaNames = make([]string, 0)
+	ctx.destructionCallbacks = make(map[string][]func() error, 0)
+	ctx.destructionCallbackNames = make([]string, 0)
+	ctx.muDestruction.Unlock()
+
+	peaFactory := ctx.GetPeaFactory()
+	destroyedPeaNames := make(map[string]bool, 0)
+	destructionErrors := make([]PeaDestructionError, 0)
+	destroy := func(peaName string, instance interface{}) {
+		destroyedPeaNames[peaName] = true
+		for _, callback := range callbacks[peaName] {
+			if err := ctx.destroyPea(peaName, callback); err != nil {
+				destructionErrors = append(destructionErrors, *err)
+			}
+		}
+		if disposable, ok := instance.(Disposable); ok {
+			if err := ctx.destroyPea(peaName, disposable.Destroy); err != nil {
+				destructionErrors = append(destructionErrors, *err)
+			}
+		}
+	}
+
+	/* peas are destroyed in the reverse order of their creation, so dependents are destroyed before their dependencies.
+	Shared peas registered from outside are not owned by the context, only their registered callbacks are invoked. */
+	for index := len(createdPeaNames) - 1; index >= 0; index-- {
+		peaName := createdPeaNames[index]
+		if destroyedPeaNames[peaName] {
+			continue
+		}
+		destroy(peaName, peaFactory.GetSharedPea(peaName))
+	}
+
+	for index := len(callbackNames) - 1; index >= 0; index-- {
+		if !destroyedPeaNames[callbackNames[index]] {
+			destroy(callbackNames[index], nil)
+		}
+	}
+
+	if len(destructionErrors) != 0 {
+		return DestructionErrors{destructionErrors}
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) destroyPea(peaName string, destroyFunc func() error) (destructionError *PeaDestructionError) {
+	defer func() {
+		if r := recover(); r != nil {
+			err := NewPeaDestructionError(peaName, recoveredError(r))
+			destructionError = &err
+		}
+		if destructionError != nil && ctx.logger != nil {
+			ctx.logger.Error(ctx, destructionError.Error())
+		}
+	}()
+	if err := destroyFunc(); err != nil {
+		destructionError := NewPeaDestructionError(peaName, err)
+		return &destructionError
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) isExcludedType(peaName string, typ goo.Type) bool {
+	/* the excluded types are matched in the same way as the component scan filters */
+	for _, excludedType := range ctx.excludedTypes {
+		if NewAssignableTypeFilter(excludedType).Matches(peaName, typ) {
+			return true
+		}
+	}
+	return false
 }
 
 func (ctx *BaseApplicationContext) GetPeaFactory() peas.ConfigurablePeaFactory {
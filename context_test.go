@@ -1,15 +1,19 @@
 package context
 
 import (
+	"github.com/procyon-projects/goo"
 	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
 type testConfigurableContextAdapter struct {
 }
 
-func (adapter testConfigurableContextAdapter) Configure() {
+func (adapter testConfigurableContextAdapter) Configure() error {
+	return nil
 
 }
 
@@ -70,5 +74,240 @@ func TestBaseApplicationContext(t *testing.T) {
 	env := core.NewStandardEnvironment()
 	baseApplicationContext.SetEnvironment(env)
 	assert.Equal(t, env, baseApplicationContext.GetEnvironment())
-	baseApplicationContext.Configure()
+	assert.Nil(t, baseApplicationContext.GetConditionEvaluationReport())
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.NotNil(t, baseApplicationContext.GetConditionEvaluationReport())
+}
+
+type testContextInitializer struct {
+	order        int
+	invokedOrder *[]int
+}
+
+func (initializer testContextInitializer) InitializeContext(context ConfigurableApplicationContext) {
+	*initializer.invokedOrder = append(*initializer.invokedOrder, initializer.order)
+	if context.GetEnvironment() == nil {
+		context.SetEnvironment(core.NewStandardEnvironment())
+	}
+}
+
+func (initializer testContextInitializer) GetOrder() int {
+	return initializer.order
+}
+
+func TestBaseApplicationContext_AddInitializer(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	assert.Panics(t, func() {
+		baseApplicationContext.AddInitializer(nil)
+	})
+
+	invokedOrder := make([]int, 0)
+	baseApplicationContext.AddInitializer(testContextInitializer{2, &invokedOrder})
+	baseApplicationContext.AddInitializer(testContextInitializer{1, &invokedOrder})
+	assert.Equal(t, 2, len(baseApplicationContext.GetInitializers()))
+
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	assert.Equal(t, []int{1, 2}, invokedOrder)
+	assert.NotNil(t, baseApplicationContext.GetEnvironment())
+}
+
+type testLazyComponent struct {
+}
+
+func newTestLazyComponent() testLazyComponent {
+	return testLazyComponent{}
+}
+
+type testEagerComponent struct {
+}
+
+func newTestEagerComponent() testEagerComponent {
+	return testEagerComponent{}
+}
+
+func (component testEagerComponent) IsLazyInit() bool {
+	return false
+}
+
+func TestBaseApplicationContext_LazyInitialization(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	logger := NewSimpleLogger()
+	writer := &logWriter{}
+	logger.log.Out = writer
+	baseApplicationContext.SetLogger(logger)
+
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app", "--" + LazyInitializationProperty + "=true"}))
+	baseApplicationContext.SetEnvironment(env)
+
+	scanner := NewComponentPeaDefinitionScanner(baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry), env)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("testLazyComponent", scanner.createPeaDefinition("testLazyComponent", goo.GetType(newTestLazyComponent)))
+	registry.RegisterPeaDefinition("testEagerComponent", scanner.createPeaDefinition("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.False(t, baseApplicationContext.ContainsSharedPea("testLazyComponent"))
+	assert.True(t, baseApplicationContext.ContainsSharedPea("testEagerComponent"))
+
+	assert.Nil(t, baseApplicationContext.Close())
+	assert.True(t, strings.Contains(writer.logMessage, "testLazyComponent"))
+
+	pea, err := baseApplicationContext.GetPea("testLazyComponent")
+	assert.Nil(t, err)
+	assert.NotNil(t, pea)
+	assert.True(t, baseApplicationContext.ContainsSharedPea("testLazyComponent"))
+}
+
+func TestScannedPeaDefinition_IsLazyInit(t *testing.T) {
+	definition := NewScannedPeaDefinition("testComponent", goo.GetType(newTestLazyComponent))
+	assert.True(t, definition.IsLazyInit(true))
+	assert.False(t, definition.IsLazyInit(false))
+
+	definition = NewScannedPeaDefinition("testComponent", goo.GetType(newTestLazyComponent), WithLazyInit(true))
+	assert.True(t, definition.IsLazyInit(false))
+}
+
+func TestBaseApplicationContext_GetPeaByAlias(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("service", NewScannedPeaDefinition("service", goo.GetType(newTestLazyComponent), WithAliases("lazyService")))
+
+	assert.Equal(t, []string{"lazyService"}, baseApplicationContext.GetAliases("service"))
+	assert.Equal(t, []string{}, baseApplicationContext.GetAliases("missing"))
+
+	pea, err := baseApplicationContext.GetPea("service")
+	assert.Nil(t, err)
+	aliasedPea, err := baseApplicationContext.GetPea("lazyService")
+	assert.Nil(t, err)
+	assert.Equal(t, pea, aliasedPea)
+	assert.False(t, baseApplicationContext.ContainsSharedPea("lazyService"))
+}
+
+func TestBaseApplicationContext_GetPrimaryPea(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	registry.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+
+	_, err := baseApplicationContext.GetPrimaryPea(dataSourceType)
+	assert.NotNil(t, err)
+	noUniquePeaError, ok := err.(NoUniquePeaError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"defaultDataSource", "userDataSource"}, noUniquePeaError.GetPeaNames())
+
+	registry.RemovePeaDefinition("userDataSource")
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource), WithPrimary(true)))
+
+	pea, err := baseApplicationContext.GetPrimaryPea(dataSourceType)
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, pea)
+
+	pea, err = baseApplicationContext.GetPeaByType(dataSourceType)
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, pea)
+}
+
+func TestBaseApplicationContext_GetPeaByQualifier(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource), WithQualifiers("users")))
+	registry.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+
+	pea, err := baseApplicationContext.GetPeaByQualifier("users", dataSourceType)
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, pea)
+
+	pea, err = baseApplicationContext.GetPeaByQualifier("defaultDataSource", dataSourceType)
+	assert.Nil(t, err)
+	assert.IsType(t, testDefaultDataSource{}, pea)
+
+	_, err = baseApplicationContext.GetPeaByQualifier("orders", dataSourceType)
+	assert.NotNil(t, err)
+}
+
+func TestBaseApplicationContext_GetPeasOfType(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	registry.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+
+	peaMap, err := baseApplicationContext.GetPeasOfType(dataSourceType)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(peaMap))
+	assert.IsType(t, testUserDataSource{}, peaMap["userDataSource"])
+	assert.IsType(t, testDefaultDataSource{}, peaMap["defaultDataSource"])
+}
+
+type testDataSourceConsumer struct {
+	dataSource testDataSource
+}
+
+func newTestDataSourceConsumer(dataSource testDataSource) testDataSourceConsumer {
+	return testDataSourceConsumer{dataSource}
+}
+
+func TestBaseApplicationContext_InjectPrimaryPea(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	registry.RegisterPeaDefinition("consumer", NewScannedPeaDefinition("consumer", goo.GetType(newTestDataSourceConsumer)))
+
+	_, err := baseApplicationContext.GetPea("consumer")
+	noUniquePeaError, ok := err.(NoUniquePeaError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"defaultDataSource", "userDataSource"}, noUniquePeaError.GetPeaNames())
+
+	registry.RemovePeaDefinition("userDataSource")
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource), WithPrimary(true)))
+	consumer, err := baseApplicationContext.GetPea("consumer")
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, consumer.(testDataSourceConsumer).dataSource)
+	assert.False(t, baseApplicationContext.ContainsSharedPea("defaultDataSource"))
+}
+
+func TestBaseApplicationContext_ExcludeType(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	registry.RegisterPeaDefinition("testEagerComponent", NewScannedPeaDefinition("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	assert.NotNil(t, baseApplicationContext.ExcludeType(nil))
+	assert.Nil(t, baseApplicationContext.ExcludeType(goo.GetType((*testDataSource)(nil))))
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.False(t, baseApplicationContext.ContainsSharedPea("userDataSource"))
+	assert.True(t, baseApplicationContext.ContainsSharedPea("testEagerComponent"))
+}
+
+type testCustomPeaFactory struct {
+	peas.ConfigurablePeaFactory
+}
+
+func TestBaseApplicationContext_WithCustomPeaFactory(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	peaFactory := peas.NewDefaultPeaFactory()
+	peaFactory.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	baseApplicationContext.ConfigurablePeaFactory = testCustomPeaFactory{peaFactory}
+	dataSourceType := goo.GetType((*testDataSource)(nil))
+
+	_, err := baseApplicationContext.GetPeaByQualifier("users", dataSourceType)
+	assert.NotNil(t, err)
+	_, err = baseApplicationContext.GetPrimaryPea(dataSourceType)
+	assert.NotNil(t, err)
+	_, err = baseApplicationContext.GetPeasOfType(dataSourceType)
+	assert.NotNil(t, err)
+	/* the lookup by type is delegated to the pea factory */
+	instance, err := baseApplicationContext.GetPeaByType(dataSourceType)
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, instance)
 }
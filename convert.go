@@ -0,0 +1,206 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type DataSize int64
+
+const (
+	Byte     DataSize = 1
+	Kilobyte          = 1000 * Byte
+	Megabyte          = 1000 * Kilobyte
+	Gigabyte          = 1000 * Megabyte
+	Terabyte          = 1000 * Gigabyte
+	Kibibyte          = 1024 * Byte
+	Mebibyte          = 1024 * Kibibyte
+	Gibibyte          = 1024 * Mebibyte
+	Tebibyte          = 1024 * Gibibyte
+)
+
+var dataSizeUnits = map[string]DataSize{
+	"b":   Byte,
+	"kb":  Kilobyte,
+	"mb":  Megabyte,
+	"gb":  Gigabyte,
+	"tb":  Terabyte,
+	"kib": Kibibyte,
+	"mib": Mebibyte,
+	"gib": Gibibyte,
+	"tib": Tebibyte,
+}
+
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+}
+
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	dataSizeType = reflect.TypeOf(DataSize(0))
+	timeType     = reflect.TypeOf(time.Time{})
+	ipType       = reflect.TypeOf(net.IP{})
+	urlType      = reflect.TypeOf(url.URL{})
+	regexpType   = reflect.TypeOf(regexp.Regexp{})
+)
+
+func (size DataSize) Bytes() int64 {
+	return int64(size)
+}
+
+func ParseDataSize(value string, defaultUnit string) (DataSize, error) {
+	value = strings.TrimSpace(value)
+	index := strings.IndexFunc(value, func(char rune) bool {
+		return unicode.IsLetter(char)
+	})
+	number, unitName := value, defaultUnit
+	if index > -1 {
+		number, unitName = strings.TrimSpace(value[:index]), value[index:]
+	}
+	if unitName == "" {
+		unitName = "B"
+	}
+	unit, ok := dataSizeUnits[strings.ToLower(unitName)]
+	if !ok {
+		return 0, errors.New("unknown data size unit '" + unitName + "'")
+	}
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil || amount < 0 {
+		return 0, errors.New("invalid data size '" + value + "'")
+	}
+	size := amount * float64(unit)
+	if size > math.MaxInt64 {
+		return 0, errors.New("data size '" + value + "' is too large")
+	}
+	return DataSize(size), nil
+}
+
+func parseDuration(value string, defaultUnit string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if defaultUnit == "" {
+		defaultUnit = "ns"
+	}
+	unit, ok := durationUnits[defaultUnit]
+	if !ok {
+		return 0, errors.New("unknown duration unit '" + defaultUnit + "'")
+	}
+	/* numbers without a unit are interpreted in the default unit */
+	if amount, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(amount * float64(unit)), nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if amount, err := strconv.ParseFloat(strings.TrimSuffix(value, "d"), 64); err == nil {
+			return time.Duration(amount * float64(durationUnits["d"])), nil
+		}
+	}
+	return time.ParseDuration(value)
+}
+
+func isDataSizeUnit(unit string) bool {
+	_, ok := dataSizeUnits[strings.ToLower(unit)]
+	return ok
+}
+
+func hasDataSizeUnit(value string) bool {
+	index := strings.IndexFunc(value, func(char rune) bool {
+		return unicode.IsLetter(char)
+	})
+	return index > 0 && isDataSizeUnit(value[index:])
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Int64) || (kind >= reflect.Uint && kind <= reflect.Uint64)
+}
+
+func isIntegerOverflow(typ reflect.Type, value int64) bool {
+	if typ.Kind() >= reflect.Uint && typ.Kind() <= reflect.Uint64 {
+		return reflect.Zero(typ).OverflowUint(uint64(value))
+	}
+	return reflect.Zero(typ).OverflowInt(value)
+}
+
+func isValueType(typ reflect.Type) bool {
+	return typ == timeType || typ == ipType || typ == urlType || typ == regexpType
+}
+
+func convertSpecialValue(propertyValue interface{}, typ reflect.Type, options bindOptions) (reflect.Value, bool, error) {
+	if propertyValue == nil {
+		return reflect.Value{}, false, nil
+	}
+	value := strings.TrimSpace(fmt.Sprint(propertyValue))
+	switch {
+	case typ == durationType:
+		duration, err := parseDuration(value, options.unit)
+		return reflect.ValueOf(duration), true, err
+	case typ == dataSizeType:
+		size, err := ParseDataSize(value, options.unit)
+		return reflect.ValueOf(size), true, err
+	case isIntegerKind(typ.Kind()) && (isDataSizeUnit(options.unit) || (options.unit == "" && hasDataSizeUnit(value))):
+		/* integers are bound as data sizes in bytes if a data size unit is given */
+		size, err := ParseDataSize(value, options.unit)
+		if err == nil && isIntegerOverflow(typ, int64(size)) {
+			err = errors.New("data size '" + value + "' overflows " + typ.String())
+		}
+		return reflect.ValueOf(size).Convert(typ), true, err
+	case typ == timeType:
+		layout := time.RFC3339
+		if options.format != "" {
+			layout = options.format
+		}
+		if namedLayout, ok := timeLayouts[layout]; ok {
+			layout = namedLayout
+		}
+		parsedTime, err := time.Parse(layout, value)
+		return reflect.ValueOf(parsedTime), true, err
+	case typ == ipType:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return reflect.Value{}, true, errors.New("invalid IP address '" + value + "'")
+		}
+		return reflect.ValueOf(ip), true, nil
+	case typ == urlType:
+		parsedUrl, err := url.Parse(value)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		return reflect.ValueOf(parsedUrl).Elem(), true, nil
+	case typ == regexpType:
+		expression, err := regexp.Compile(value)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+		return reflect.ValueOf(expression).Elem(), true, nil
+	}
+	return reflect.Value{}, false, nil
+}
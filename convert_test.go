@@ -0,0 +1,120 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseDataSize(t *testing.T) {
+	size, err := ParseDataSize("10MB", "")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10000000), size.Bytes())
+
+	size, err = ParseDataSize("1.5 KiB", "")
+	assert.Nil(t, err)
+	assert.Equal(t, DataSize(1536), size)
+
+	size, err = ParseDataSize("2", "GiB")
+	assert.Nil(t, err)
+	assert.Equal(t, 2*Gibibyte, size)
+
+	size, err = ParseDataSize("512", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 512*Byte, size)
+
+	_, err = ParseDataSize("10XB", "")
+	assert.Equal(t, "unknown data size unit 'XB'", err.Error())
+
+	_, err = ParseDataSize("-1KB", "")
+	assert.NotNil(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	duration, err := parseDuration("30s", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 30*time.Second, duration)
+
+	duration, err = parseDuration("250", "ms")
+	assert.Nil(t, err)
+	assert.Equal(t, 250*time.Millisecond, duration)
+
+	duration, err = parseDuration("2d", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 48*time.Hour, duration)
+
+	_, err = parseDuration("1", "weeks")
+	assert.NotNil(t, err)
+}
+
+type testConversionProperties struct {
+	Timeout     time.Duration    `json:"timeout"`
+	Interval    time.Duration    `json:"interval" unit:"s" default:"5"`
+	Delays      []time.Duration  `json:"delays" unit:"ms"`
+	MaxUpload   int64            `json:"max-upload"`
+	BufferSize  int              `json:"buffer-size" unit:"KiB" default:"4"`
+	CacheSize   DataSize         `json:"cache-size" default:"1GB"`
+	StartDate   time.Time        `json:"start-date" format:"DateOnly"`
+	CreatedAt   time.Time        `json:"created-at"`
+	Address     net.IP           `json:"address"`
+	Endpoint    url.URL          `json:"endpoint"`
+	Fallback    *url.URL         `json:"fallback"`
+	NamePattern *regexp.Regexp   `json:"name-pattern"`
+	Patterns    []*regexp.Regexp `json:"patterns"`
+}
+
+func (testConversionProperties) GetConfigurationPrefix() string {
+	return "conversion"
+}
+
+func TestConfigurationPropertiesBinder_BindConversions(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--conversion.timeout=1m30s", "--conversion.delays=10,20", "--conversion.max-upload=10MB",
+		"--conversion.start-date=2020-01-02", "--conversion.created-at=2020-01-02T15:04:05Z",
+		"--conversion.address=127.0.0.1", "--conversion.endpoint=https://localhost:8080/api",
+		"--conversion.fallback=http://fallback", "--conversion.name-pattern=^[a-z]+$", "--conversion.patterns=a+,b+",
+	}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testConversionProperties{}
+	assert.Nil(t, propertiesBindingProcessor.Bind(properties))
+	assert.Equal(t, 90*time.Second, properties.Timeout)
+	assert.Equal(t, 5*time.Second, properties.Interval)
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, properties.Delays)
+	assert.Equal(t, int64(10000000), properties.MaxUpload)
+	assert.Equal(t, 4096, properties.BufferSize)
+	assert.Equal(t, Gigabyte, properties.CacheSize)
+	assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), properties.StartDate)
+	assert.Equal(t, time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC), properties.CreatedAt)
+	assert.True(t, net.ParseIP("127.0.0.1").Equal(properties.Address))
+	assert.Equal(t, "localhost:8080", properties.Endpoint.Host)
+	assert.Equal(t, "/api", properties.Endpoint.Path)
+	assert.Equal(t, "fallback", properties.Fallback.Host)
+	assert.True(t, properties.NamePattern.MatchString("test"))
+	assert.Equal(t, 2, len(properties.Patterns))
+	assert.Equal(t, "b+", properties.Patterns[1].String())
+}
+
+func TestConfigurationPropertiesBinder_BindInvalidConversions(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--conversion.timeout=soon", "--conversion.start-date=02.01.2020", "--conversion.address=localhost",
+		"--conversion.name-pattern=[",
+	}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	err := propertiesBindingProcessor.Bind(&testConversionProperties{})
+	assert.NotNil(t, err)
+	failures := err.(BindError).GetFailures()
+	assert.Equal(t, 4, len(failures))
+	assert.Equal(t, "conversion.timeout", failures[0].GetPropertyName())
+	assert.Equal(t, "conversion.start-date", failures[1].GetPropertyName())
+	assert.Equal(t, "conversion.address", failures[2].GetPropertyName())
+	assert.Equal(t, "invalid IP address 'localhost'", failures[2].GetCause().(PropertyConversionError).GetCause().Error())
+	assert.Equal(t, "conversion.name-pattern", failures[3].GetPropertyName())
+}
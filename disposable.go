@@ -0,0 +1,80 @@
+package context
+
+import (
+	"github.com/procyon-projects/procyon-peas"
+	"strings"
+)
+
+type Disposable interface {
+	Destroy() error
+}
+
+type PeaDestructionError struct {
+	peaName string
+	cause   error
+}
+
+func NewPeaDestructionError(peaName string, cause error) PeaDestructionError {
+	return PeaDestructionError{
+		peaName,
+		cause,
+	}
+}
+
+func (err PeaDestructionError) GetPeaName() string {
+	return err.peaName
+}
+
+func (err PeaDestructionError) GetCause() error {
+	return err.cause
+}
+
+func (err PeaDestructionError) Unwrap() error {
+	return err.cause
+}
+
+func (err PeaDestructionError) Error() string {
+	return "destruction of the pea '" + err.peaName + "' failed : " + err.cause.Error()
+}
+
+type DestructionErrors struct {
+	errors []PeaDestructionError
+}
+
+func (err DestructionErrors) GetErrors() []PeaDestructionError {
+	return err.errors
+}
+
+func (err DestructionErrors) Error() string {
+	messages := make([]string, 0)
+	for _, destructionError := range err.errors {
+		messages = append(messages, destructionError.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+type destructionTrackingPeaProcessor struct {
+	ctx *BaseApplicationContext
+}
+
+func newDestructionTrackingPeaProcessor(ctx *BaseApplicationContext) destructionTrackingPeaProcessor {
+	return destructionTrackingPeaProcessor{
+		ctx,
+	}
+}
+
+func (processor destructionTrackingPeaProcessor) BeforePeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	return pea, nil
+}
+
+func (processor destructionTrackingPeaProcessor) AfterPeaInitialization(peaName string, pea interface{}) (interface{}, error) {
+	peaDefinitionRegistry, ok := processor.ctx.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	if ok {
+		peaDefinition := peaDefinitionRegistry.GetPeaDefinition(peaName)
+		if peaDefinition != nil && peaDefinition.GetScope() != peas.SharedScope {
+			return pea, nil
+		}
+	}
+	processor.ctx.registerCreatedPea(peaName)
+	return pea, nil
+}
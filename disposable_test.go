@@ -0,0 +1,169 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testDestroyedPeas = make([]string, 0)
+
+type testDisposableRepository struct {
+}
+
+func newTestDisposableRepository() testDisposableRepository {
+	return testDisposableRepository{}
+}
+
+func (repository testDisposableRepository) Destroy() error {
+	testDestroyedPeas = append(testDestroyedPeas, "repository")
+	return nil
+}
+
+type testDisposableService struct {
+}
+
+func newTestDisposableService(repository testDisposableRepository) testDisposableService {
+	return testDisposableService{}
+}
+
+func (service testDisposableService) Destroy() error {
+	testDestroyedPeas = append(testDestroyedPeas, "service")
+	return errors.New("test-destroy-error")
+}
+
+type testDisposableRequestComponent struct {
+}
+
+func newTestDisposableRequestComponent() testDisposableRequestComponent {
+	return testDisposableRequestComponent{}
+}
+
+func (component testDisposableRequestComponent) GetPeaScope() peas.PeaScope {
+	return RequestScope
+}
+
+func (component testDisposableRequestComponent) Destroy() error {
+	testDestroyedPeas = append(testDestroyedPeas, "request")
+	return nil
+}
+
+func TestBaseApplicationContext_DestroyPeas(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	logger := NewSimpleLogger()
+	writer := &logWriter{}
+	logger.log.Out = writer
+	baseApplicationContext.SetLogger(logger)
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("aService", NewScannedPeaDefinition("aService", goo.GetType(newTestDisposableService)))
+	registry.RegisterPeaDefinition("zRepository", NewScannedPeaDefinition("zRepository", goo.GetType(newTestDisposableRepository)))
+
+	assert.Panics(t, func() {
+		baseApplicationContext.RegisterDestructionCallback("", nil)
+	})
+	baseApplicationContext.RegisterDestructionCallback("aService", func() error {
+		testDestroyedPeas = append(testDestroyedPeas, "service-callback")
+		return nil
+	})
+	baseApplicationContext.RegisterDestructionCallback("external", func() error {
+		panic("test-panic")
+	})
+
+	testDestroyedPeas = make([]string, 0)
+	assert.Nil(t, baseApplicationContext.Configure())
+	err := baseApplicationContext.Close()
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"service-callback", "service", "repository"}, testDestroyedPeas)
+
+	destructionErrors, ok := err.(DestructionErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(destructionErrors.GetErrors()))
+	assert.Equal(t, "aService", destructionErrors.GetErrors()[0].GetPeaName())
+	assert.Equal(t, "test-destroy-error", destructionErrors.GetErrors()[0].GetCause().Error())
+	assert.Equal(t, "external", destructionErrors.GetErrors()[1].GetPeaName())
+	assert.True(t, strings.Contains(writer.logMessage, "test-panic"))
+
+	assert.Nil(t, baseApplicationContext.Close())
+}
+
+func TestRequestContext_DestroyDisposablePeas(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	scanner := NewComponentPeaDefinitionScanner(registry, core.NewStandardEnvironment())
+	registry.RegisterPeaDefinition("requestComponent", scanner.createPeaDefinition("requestComponent", goo.GetType(newTestDisposableRequestComponent)))
+
+	testDestroyedPeas = make([]string, 0)
+	requestContext := baseApplicationContext.NewRequestContext("request-id")
+	_, err := requestContext.GetPea("requestComponent")
+	assert.Nil(t, err)
+	_, err = requestContext.GetPea("requestComponent")
+	assert.Nil(t, err)
+	requestContext.Close()
+	assert.Equal(t, []string{"request"}, testDestroyedPeas)
+}
+
+func TestBaseApplicationContext_DestroyOnlyOwnedPeas(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("zRepository", NewScannedPeaDefinition("zRepository", goo.GetType(newTestDisposableRepository)))
+	assert.Nil(t, baseApplicationContext.RegisterSharedPea("externalService", newTestDisposableService(testDisposableRepository{})))
+
+	testDestroyedPeas = make([]string, 0)
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.Nil(t, baseApplicationContext.Close())
+	assert.Equal(t, []string{"repository"}, testDestroyedPeas)
+}
+
+type testClosedEventListener struct {
+	closed *bool
+}
+
+func (listener testClosedEventListener) GetApplicationListenerName() string {
+	return "github.com.procyon.projects.testClosedEventListener"
+}
+
+func (listener testClosedEventListener) SubscribeEvents() []ApplicationEventId {
+	return []ApplicationEventId{
+		ApplicationContextClosedEventId(),
+	}
+}
+
+func (listener testClosedEventListener) OnApplicationEvent(context Context, event ApplicationEvent) {
+	/* closing the context again must not block while it is being closed */
+	*listener.closed = context.(*BaseApplicationContext).Close() == nil
+}
+
+func TestBaseApplicationContext_CloseWithoutHoldingTheLock(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	closed := false
+	baseApplicationContext.AddApplicationListener(testClosedEventListener{&closed})
+	baseApplicationContext.RegisterDestructionCallback("external", func() error {
+		baseApplicationContext.AddFailureAnalyzer(FailureAnalyzerFunc(func(err error) *FailureAnalysis {
+			return nil
+		}))
+		return nil
+	})
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	done := make(chan error)
+	go func() {
+		done <- baseApplicationContext.Close()
+	}()
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "context could not be used while it was being closed")
+	}
+	assert.True(t, closed)
+}
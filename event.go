@@ -11,6 +11,8 @@ var applicationContextStartedEventId = GetEventId("github.com.procyon.Applicatio
 var applicationContextStoppedEventId = GetEventId("github.com.procyon.ApplicationContextStoppedEvent")
 var applicationContextRefreshedEventId = GetEventId("github.com.procyon.ApplicationContextRefreshedEvent")
 var applicationContextClosedEventId = GetEventId("github.com.procyon.ApplicationContextClosedEvent")
+var applicationFailedEventId = GetEventId("github.com.procyon.ApplicationFailedEvent")
+var environmentChangedEventId = GetEventId("github.com.procyon.EnvironmentChangedEvent")
 
 func ApplicationContextEventId() ApplicationEventId {
 	return applicationContextEventId
@@ -32,6 +34,14 @@ func ApplicationContextClosedEventId() ApplicationEventId {
 	return applicationContextClosedEventId
 }
 
+func ApplicationFailedEventId() ApplicationEventId {
+	return applicationFailedEventId
+}
+
+func EnvironmentChangedEventId() ApplicationEventId {
+	return environmentChangedEventId
+}
+
 func GetEventId(eventName string) ApplicationEventId {
 	if len(eventName) > 0 {
 		hash := uint64(0)
@@ -182,3 +192,79 @@ func (event ApplicationContextClosedEvent) GetTimestamp() int64 {
 func (event ApplicationContextClosedEvent) GetApplicationContext() ApplicationContext {
 	return event.source
 }
+
+type ApplicationFailedEvent struct {
+	source    ApplicationContext
+	err       error
+	timestamp int64
+}
+
+func NewApplicationFailedEvent(source ApplicationContext, err error) ApplicationFailedEvent {
+	return ApplicationFailedEvent{
+		source:    source,
+		err:       err,
+		timestamp: time.Now().Unix(),
+	}
+}
+
+func (event ApplicationFailedEvent) GetEventId() ApplicationEventId {
+	return applicationFailedEventId
+}
+
+func (event ApplicationFailedEvent) GetParentEventId() ApplicationEventId {
+	return applicationContextEventId
+}
+
+func (event ApplicationFailedEvent) GetSource() interface{} {
+	return event.source
+}
+
+func (event ApplicationFailedEvent) GetTimestamp() int64 {
+	return event.timestamp
+}
+
+func (event ApplicationFailedEvent) GetApplicationContext() ApplicationContext {
+	return event.source
+}
+
+func (event ApplicationFailedEvent) GetError() error {
+	return event.err
+}
+
+type EnvironmentChangedEvent struct {
+	source    ApplicationContext
+	keys      []string
+	timestamp int64
+}
+
+func NewEnvironmentChangedEvent(source ApplicationContext, keys []string) EnvironmentChangedEvent {
+	return EnvironmentChangedEvent{
+		source:    source,
+		keys:      keys,
+		timestamp: time.Now().Unix(),
+	}
+}
+
+func (event EnvironmentChangedEvent) GetEventId() ApplicationEventId {
+	return environmentChangedEventId
+}
+
+func (event EnvironmentChangedEvent) GetParentEventId() ApplicationEventId {
+	return applicationContextEventId
+}
+
+func (event EnvironmentChangedEvent) GetSource() interface{} {
+	return event.source
+}
+
+func (event EnvironmentChangedEvent) GetTimestamp() int64 {
+	return event.timestamp
+}
+
+func (event EnvironmentChangedEvent) GetApplicationContext() ApplicationContext {
+	return event.source
+}
+
+func (event EnvironmentChangedEvent) GetKeys() []string {
+	return event.keys
+}
@@ -1,6 +1,7 @@
 package context
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -36,3 +37,17 @@ func TestApplicationContextClosedEvent(t *testing.T) {
 	event := NewApplicationContextClosedEvent(context)
 	testApplicationContextEvent(t, event, ApplicationContextClosedEventId(), ApplicationContextEventId())
 }
+
+func TestApplicationFailedEvent(t *testing.T) {
+	context := &testContext{}
+	event := NewApplicationFailedEvent(context, errors.New("test-error"))
+	testApplicationContextEvent(t, event, ApplicationFailedEventId(), ApplicationContextEventId())
+	assert.Equal(t, "test-error", event.GetError().Error())
+}
+
+func TestEnvironmentChangedEvent(t *testing.T) {
+	context := &testContext{}
+	event := NewEnvironmentChangedEvent(context, []string{"server.port"})
+	testApplicationContextEvent(t, event, EnvironmentChangedEventId(), ApplicationContextEventId())
+	assert.Equal(t, []string{"server.port"}, event.GetKeys())
+}
@@ -0,0 +1,200 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type FailureAnalysis struct {
+	description string
+	action      string
+	cause       error
+}
+
+func NewFailureAnalysis(description string, action string, cause error) *FailureAnalysis {
+	return &FailureAnalysis{
+		description,
+		action,
+		cause,
+	}
+}
+
+func (analysis *FailureAnalysis) GetDescription() string {
+	return analysis.description
+}
+
+func (analysis *FailureAnalysis) GetAction() string {
+	return analysis.action
+}
+
+func (analysis *FailureAnalysis) GetCause() error {
+	return analysis.cause
+}
+
+func (analysis *FailureAnalysis) String() string {
+	var builder strings.Builder
+	builder.WriteString("\n\n***************************\n")
+	builder.WriteString("APPLICATION FAILED TO START\n")
+	builder.WriteString("***************************\n\n")
+	builder.WriteString("Description:\n\n")
+	builder.WriteString(analysis.description + "\n")
+	if analysis.action != "" {
+		builder.WriteString("\nAction:\n\n")
+		builder.WriteString(analysis.action + "\n")
+	}
+	return builder.String()
+}
+
+type FailureAnalyzer interface {
+	Analyze(err error) *FailureAnalysis
+}
+
+type FailureAnalyzerFunc func(err error) *FailureAnalysis
+
+func (fun FailureAnalyzerFunc) Analyze(err error) *FailureAnalysis {
+	return fun(err)
+}
+
+func getDefaultFailureAnalyzers() []FailureAnalyzer {
+	return []FailureAnalyzer{
+		FailureAnalyzerFunc(analyzeDependencyCycle),
+		FailureAnalyzerFunc(analyzeNoUniquePea),
+		FailureAnalyzerFunc(analyzeAmbiguousDependency),
+		FailureAnalyzerFunc(analyzePeaNameCollision),
+		FailureAnalyzerFunc(analyzeBindError),
+		FailureAnalyzerFunc(analyzeValidationError),
+		FailureAnalyzerFunc(analyzePropertyConversion),
+	}
+}
+
+func analyzeFailure(analyzers []FailureAnalyzer, err error) *FailureAnalysis {
+	if err == nil {
+		return nil
+	}
+	for _, analyzer := range analyzers {
+		if analysis := analyzer.Analyze(err); analysis != nil {
+			return analysis
+		}
+	}
+	return nil
+}
+
+func recoveredError(value interface{}) error {
+	if err, ok := value.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(value))
+}
+
+func analyzeDependencyCycle(err error) *FailureAnalysis {
+	var cycleError DependencyCycleError
+	if !errors.As(err, &cycleError) {
+		return nil
+	}
+	description := "The dependencies of some of the peas in the application context form a cycle:\n\n\t" +
+		strings.Join(cycleError.GetPath(), "\n\t-> ")
+	return NewFailureAnalysis(description,
+		"Update your application to remove the dependency cycle between the peas.", err)
+}
+
+func analyzeNoUniquePea(err error) *FailureAnalysis {
+	var noUniquePeaError NoUniquePeaError
+	if !errors.As(err, &noUniquePeaError) {
+		return nil
+	}
+	if len(noUniquePeaError.GetPeaNames()) == 0 {
+		return NewFailureAnalysis("No pea of the type "+noUniquePeaError.GetTypeName()+" could be found.",
+			"Consider registering a component of the type "+noUniquePeaError.GetTypeName()+".", err)
+	}
+	return NewFailureAnalysis("A single pea of the type "+noUniquePeaError.GetTypeName()+" was required, but "+
+		fmt.Sprint(len(noUniquePeaError.GetPeaNames()))+" were found:\n\t- "+strings.Join(noUniquePeaError.GetPeaNames(), "\n\t- "),
+		"Consider marking one of the peas as primary by implementing the interface context.PrimaryComponent, "+
+			"or using qualifiers by implementing the interface context.QualifiedComponent.", err)
+}
+
+/* procyon-peas has no typed errors for ambiguous dependencies, so its messages are matched */
+var ambiguousDependencyMessages = []string{
+	"Determining which dependency is used cannot be distinguished",
+	"there is more than one candidate pea definition",
+}
+
+func isAmbiguousDependencyError(err error) bool {
+	for _, message := range ambiguousDependencyMessages {
+		if strings.Contains(err.Error(), message) {
+			return true
+		}
+	}
+	return false
+}
+
+func analyzeAmbiguousDependency(err error) *FailureAnalysis {
+	if !isAmbiguousDependencyError(err) {
+		return nil
+	}
+	return NewFailureAnalysis("A dependency of a pea is ambiguous, more than one pea matches the required type : "+err.Error(),
+		"Consider narrowing the type of the dependency, excluding the other peas from the component scan, "+
+			"or looking the pea up by using GetPrimaryPea or GetPeaByQualifier.", err)
+}
+
+func analyzePeaNameCollision(err error) *FailureAnalysis {
+	var collisionError PeaNameCollisionError
+	if !errors.As(err, &collisionError) {
+		return nil
+	}
+	return NewFailureAnalysis("The pea '"+collisionError.GetPeaName()+"' of the type "+collisionError.GetTypeName()+
+		" could not be registered. A pea with that name has already been registered by the type "+collisionError.GetExistingTypeName()+".",
+		"Consider renaming one of the peas by implementing the interface context.NamedComponent.", err)
+}
+
+func analyzeBindError(err error) *FailureAnalysis {
+	var bindError BindError
+	if !errors.As(err, &bindError) {
+		return nil
+	}
+	var builder strings.Builder
+	builder.WriteString("Failed to bind the properties with the prefix '" + bindError.GetPrefix() + "' :")
+	for _, failure := range bindError.GetFailures() {
+		builder.WriteString("\n\n    Property: " + failure.GetPropertyName())
+		if failure.GetValue() != nil {
+			builder.WriteString("\n    Value: " + fmt.Sprint(failure.GetValue()))
+		}
+		if failure.GetSource() != "" {
+			builder.WriteString("\n    Origin: " + failure.GetSource())
+		}
+		builder.WriteString("\n    Type: " + failure.GetTypeName())
+		builder.WriteString("\n    Reason: " + failure.GetCause().Error())
+	}
+	return NewFailureAnalysis(builder.String(),
+		"Update the properties listed above so that each of them is specified and can be converted to the type of its field.", err)
+}
+
+func analyzeValidationError(err error) *FailureAnalysis {
+	var validationError ValidationError
+	if !errors.As(err, &validationError) {
+		return nil
+	}
+	var builder strings.Builder
+	builder.WriteString("The properties with the prefix '" + validationError.GetPrefix() + "' are not valid :")
+	for _, failure := range validationError.GetFailures() {
+		builder.WriteString("\n\n    Property: " + failure.GetPropertyName())
+		if failure.GetValue() != nil {
+			builder.WriteString("\n    Value: " + fmt.Sprint(failure.GetValue()))
+		}
+		builder.WriteString("\n    Constraint: " + failure.GetConstraint())
+		builder.WriteString("\n    Reason: " + failure.GetCause().Error())
+	}
+	return NewFailureAnalysis(builder.String(),
+		"Update the properties listed above so that they satisfy their constraints.", err)
+}
+
+func analyzePropertyConversion(err error) *FailureAnalysis {
+	var conversionError PropertyConversionError
+	if !errors.As(err, &conversionError) {
+		return nil
+	}
+	return NewFailureAnalysis("Failed to bind the property '"+conversionError.GetPropertyName()+"' with the value '"+
+		fmt.Sprint(conversionError.GetValue())+"' to the type "+conversionError.GetTypeName()+" : "+conversionError.GetCause().Error(),
+		"Update the value of the property '"+conversionError.GetPropertyName()+"' so that it can be converted to "+
+			conversionError.GetTypeName()+".", err)
+}
@@ -0,0 +1,134 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFailureAnalysis_String(t *testing.T) {
+	analysis := NewFailureAnalysis("test-description", "test-action", errors.New("test-error"))
+	assert.Equal(t, "test-description", analysis.GetDescription())
+	assert.Equal(t, "test-action", analysis.GetAction())
+	assert.Equal(t, "test-error", analysis.GetCause().Error())
+	assert.True(t, strings.Contains(analysis.String(), "APPLICATION FAILED TO START"))
+	assert.True(t, strings.Contains(analysis.String(), "Description:\n\ntest-description"))
+	assert.True(t, strings.Contains(analysis.String(), "Action:\n\ntest-action"))
+}
+
+func TestDefaultFailureAnalyzers(t *testing.T) {
+	analyzers := getDefaultFailureAnalyzers()
+
+	analysis := analyzeFailure(analyzers, NewDependencyCycleError([]string{"a", "b", "a"}))
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "a\n\t-> b\n\t-> a"))
+
+	analysis = analyzeFailure(analyzers, NewNoUniquePeaError("testDataSource", []string{"a", "b"}))
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "but 2 were found"))
+
+	analysis = analyzeFailure(analyzers, errors.New("Determining which dependency is used cannot be distinguished : testDataSource"))
+	assert.NotNil(t, analysis)
+
+	analysis = analyzeFailure(analyzers, NewPeaNameCollisionError("service", "existingType", "newType"))
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "existingType"))
+
+	analysis = analyzeFailure(analyzers, NewBindError("server", []PropertyBindingFailure{
+		NewPropertyBindingFailure("server.url", "", nil, "string", errors.New("test-error")),
+		NewPropertyBindingFailure("server.port", "commandLineArgs", "80x", "uint16", errors.New("test-error")),
+	}))
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "Property: server.url"))
+	assert.True(t, strings.Contains(analysis.GetDescription(), "Property: server.port\n    Value: 80x\n    Origin: commandLineArgs"))
+
+	analysis = analyzeFailure(analyzers, NewPropertyConversionError("server.port", "invalid", "uint16", errors.New("test-error")))
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "'invalid'"))
+
+	assert.Nil(t, analyzeFailure(analyzers, errors.New("unknown error")))
+	assert.Nil(t, analyzeFailure(analyzers, nil))
+}
+
+type testFailingInitializer struct {
+}
+
+func (initializer testFailingInitializer) InitializeContext(context ConfigurableApplicationContext) {
+	panic("test-failure")
+}
+
+func TestBaseApplicationContext_ConfigureWithFailure(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	logger := NewSimpleLogger()
+	writer := &logWriter{}
+	logger.log.Out = writer
+	baseApplicationContext.SetLogger(logger)
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	baseApplicationContext.AddInitializer(testFailingInitializer{})
+
+	assert.Panics(t, func() {
+		baseApplicationContext.AddFailureAnalyzer(nil)
+	})
+	baseApplicationContext.AddFailureAnalyzer(FailureAnalyzerFunc(func(err error) *FailureAnalysis {
+		if err.Error() == "test-failure" {
+			return NewFailureAnalysis("test-description", "test-action", err)
+		}
+		return nil
+	}))
+
+	err := baseApplicationContext.Configure()
+	assert.NotNil(t, err)
+	assert.Equal(t, "test-failure", err.Error())
+	assert.True(t, strings.Contains(writer.logMessage, "test-description"))
+	assert.True(t, strings.Contains(writer.logMessage, "test-action"))
+
+	assert.Panics(t, func() {
+		baseApplicationContext.MustConfigure()
+	})
+}
+
+/* the messages are matched against the errors of the procyon-peas version in go.mod */
+func TestIsAmbiguousDependencyError(t *testing.T) {
+	peaFactory := peas.NewDefaultPeaFactory()
+	peaFactory.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	peaFactory.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+	peaFactory.RegisterPeaDefinition("testGraphService", NewScannedPeaDefinition("testGraphService", goo.GetType(newTestGraphService)))
+
+	_, err := peaFactory.GetPeaByType(goo.GetType((*testDataSource)(nil)))
+	assert.NotNil(t, err)
+	assert.True(t, isAmbiguousDependencyError(err))
+
+	func() {
+		defer func() {
+			r := recover()
+			assert.NotNil(t, r)
+			assert.True(t, isAmbiguousDependencyError(recoveredError(r)))
+		}()
+		peaFactory.GetPea("testGraphService")
+	}()
+
+	assert.False(t, isAmbiguousDependencyError(errors.New("test-error")))
+}
+
+type testFailureAnalyzerInitializer struct {
+}
+
+func (initializer testFailureAnalyzerInitializer) InitializeContext(context ConfigurableApplicationContext) {
+	context.(*BaseApplicationContext).AddFailureAnalyzer(FailureAnalyzerFunc(func(err error) *FailureAnalysis {
+		return nil
+	}))
+}
+
+func TestBaseApplicationContext_AddFailureAnalyzerWhileConfiguring(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	baseApplicationContext.AddInitializer(testFailureAnalyzerInitializer{})
+
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.Equal(t, len(getDefaultFailureAnalyzers())+1, len(baseApplicationContext.GetFailureAnalyzers()))
+}
@@ -0,0 +1,284 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const componentScanFilters = "github.com.procyon.context.componentScanFilters"
+
+const (
+	ComponentScanIncludePackagesProperty = "procyon.component-scan.include-packages"
+	ComponentScanExcludePackagesProperty = "procyon.component-scan.exclude-packages"
+	ComponentScanIncludeTypesProperty    = "procyon.component-scan.include-types"
+	ComponentScanExcludeTypesProperty    = "procyon.component-scan.exclude-types"
+)
+
+type ComponentFilter interface {
+	Matches(componentName string, componentType goo.Type) bool
+}
+
+type ComponentFilterFunc func(componentName string, componentType goo.Type) bool
+
+func (fun ComponentFilterFunc) Matches(componentName string, componentType goo.Type) bool {
+	return fun(componentName, componentType)
+}
+
+func getComponentReturnType(componentType goo.Type) goo.Type {
+	if componentType.IsFunction() {
+		return componentType.ToFunctionType().GetFunctionReturnTypes()[0]
+	}
+	return componentType
+}
+
+func getComponentTypeName(componentType goo.Type) string {
+	goType := getComponentReturnType(componentType).GetGoType()
+	if goType.PkgPath() == "" {
+		return goType.Name()
+	}
+	return goType.PkgPath() + "." + goType.Name()
+}
+
+type PackageFilter struct {
+	patterns []*regexp.Regexp
+}
+
+func NewPackageFilter(patterns ...string) (PackageFilter, error) {
+	filter := PackageFilter{
+		patterns: make([]*regexp.Regexp, 0),
+	}
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			return filter, errors.New("package pattern must not be empty")
+		}
+		expression, err := regexp.Compile(globToRegexp(strings.TrimSpace(pattern)))
+		if err != nil {
+			return filter, err
+		}
+		filter.patterns = append(filter.patterns, expression)
+	}
+	return filter, nil
+}
+
+func (filter PackageFilter) Matches(componentName string, componentType goo.Type) bool {
+	packagePath := getComponentReturnType(componentType).GetGoType().PkgPath()
+	for _, pattern := range filter.patterns {
+		if pattern.MatchString(packagePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func globToRegexp(glob string) string {
+	expression := strings.Builder{}
+	expression.WriteString("^")
+	for index := 0; index < len(glob); index++ {
+		character := glob[index]
+		switch character {
+		case '*':
+			if index+1 < len(glob) && glob[index+1] == '*' {
+				expression.WriteString(".*")
+				index++
+			} else {
+				expression.WriteString("[^/]*")
+			}
+		case '?':
+			expression.WriteString("[^/]")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(character)))
+		}
+	}
+	expression.WriteString("$")
+	return expression.String()
+}
+
+type TypeNameFilter struct {
+	expression *regexp.Regexp
+}
+
+func NewTypeNameFilter(expression string) (TypeNameFilter, error) {
+	compiledExpression, err := regexp.Compile(expression)
+	if err != nil {
+		return TypeNameFilter{}, err
+	}
+	return TypeNameFilter{
+		compiledExpression,
+	}, nil
+}
+
+func (filter TypeNameFilter) Matches(componentName string, componentType goo.Type) bool {
+	return filter.expression.MatchString(getComponentTypeName(componentType))
+}
+
+type AssignableTypeFilter struct {
+	typ goo.Type
+}
+
+func NewAssignableTypeFilter(typ goo.Type) AssignableTypeFilter {
+	if typ == nil {
+		panic("Type must not be null")
+	}
+	return AssignableTypeFilter{
+		typ,
+	}
+}
+
+func (filter AssignableTypeFilter) Matches(componentName string, componentType goo.Type) bool {
+	returnType := getComponentReturnType(componentType)
+	if filter.typ.IsInterface() {
+		return returnType.IsStruct() && returnType.ToStructType().Implements(filter.typ.ToInterfaceType())
+	} else if filter.typ.IsStruct() && returnType.IsStruct() {
+		return filter.typ.GetGoType() == returnType.GetGoType() || returnType.ToStructType().EmbeddedStruct(filter.typ.ToStructType())
+	}
+	return false
+}
+
+type ComponentFilters struct {
+	includeFilters []ComponentFilter
+	excludeFilters []ComponentFilter
+	mu             sync.RWMutex
+}
+
+func NewComponentFilters() *ComponentFilters {
+	return &ComponentFilters{
+		includeFilters: make([]ComponentFilter, 0),
+		excludeFilters: make([]ComponentFilter, 0),
+		mu:             sync.RWMutex{},
+	}
+}
+
+func (filters *ComponentFilters) AddIncludeFilter(filter ComponentFilter) {
+	if filter == nil {
+		panic("Filter must not be null")
+	}
+	filters.mu.Lock()
+	filters.includeFilters = append(filters.includeFilters, filter)
+	filters.mu.Unlock()
+}
+
+func (filters *ComponentFilters) AddExcludeFilter(filter ComponentFilter) {
+	if filter == nil {
+		panic("Filter must not be null")
+	}
+	filters.mu.Lock()
+	filters.excludeFilters = append(filters.excludeFilters, filter)
+	filters.mu.Unlock()
+}
+
+func (filters *ComponentFilters) GetIncludeFilters() []ComponentFilter {
+	filters.mu.RLock()
+	defer filters.mu.RUnlock()
+	return append([]ComponentFilter{}, filters.includeFilters...)
+}
+
+func (filters *ComponentFilters) GetExcludeFilters() []ComponentFilter {
+	filters.mu.RLock()
+	defer filters.mu.RUnlock()
+	return append([]ComponentFilter{}, filters.excludeFilters...)
+}
+
+func (filters *ComponentFilters) AddAll(other *ComponentFilters) {
+	if other == nil {
+		return
+	}
+	for _, filter := range other.GetIncludeFilters() {
+		filters.AddIncludeFilter(filter)
+	}
+	for _, filter := range other.GetExcludeFilters() {
+		filters.AddExcludeFilter(filter)
+	}
+}
+
+func (filters *ComponentFilters) Matches(componentName string, componentType goo.Type) bool {
+	return filters.matches(componentName, componentType, true)
+}
+
+func (filters *ComponentFilters) matches(componentName string, componentType goo.Type, applyIncludeFilters bool) bool {
+	for _, filter := range filters.GetExcludeFilters() {
+		if filter.Matches(componentName, componentType) {
+			return false
+		}
+	}
+	includeFilters := filters.GetIncludeFilters()
+	if len(includeFilters) == 0 || !applyIncludeFilters {
+		return true
+	}
+	for _, filter := range includeFilters {
+		if filter.Matches(componentName, componentType) {
+			return true
+		}
+	}
+	return false
+}
+
+func isContextComponent(componentType goo.Type) bool {
+	/* the include filters scope the components of the application, the ones of the context are always needed */
+	componentTypeName := getComponentTypeName(componentType)
+	for _, contextComponent := range []interface{}{NewConfigurationPropertiesBindingProcessor} {
+		if componentTypeName == getComponentTypeName(goo.GetType(contextComponent)) {
+			return true
+		}
+	}
+	return false
+}
+
+func getComponentFiltersFromEnvironment(env core.Environment) (*ComponentFilters, error) {
+	filters := NewComponentFilters()
+	if env == nil {
+		return filters, nil
+	}
+
+	if patterns := getPropertyValues(env, ComponentScanIncludePackagesProperty); len(patterns) != 0 {
+		filter, err := NewPackageFilter(patterns...)
+		if err != nil {
+			return nil, err
+		}
+		filters.AddIncludeFilter(filter)
+	}
+	if patterns := getPropertyValues(env, ComponentScanExcludePackagesProperty); len(patterns) != 0 {
+		filter, err := NewPackageFilter(patterns...)
+		if err != nil {
+			return nil, err
+		}
+		filters.AddExcludeFilter(filter)
+	}
+	for _, expression := range getPropertyValues(env, ComponentScanIncludeTypesProperty) {
+		filter, err := NewTypeNameFilter(expression)
+		if err != nil {
+			return nil, err
+		}
+		filters.AddIncludeFilter(filter)
+	}
+	for _, expression := range getPropertyValues(env, ComponentScanExcludeTypesProperty) {
+		filter, err := NewTypeNameFilter(expression)
+		if err != nil {
+			return nil, err
+		}
+		filters.AddExcludeFilter(filter)
+	}
+	return filters, nil
+}
+
+func getPropertyValues(env core.Environment, propertyName string) []string {
+	values := make([]string, 0)
+	if !env.ContainsProperty(propertyName) {
+		return values
+	}
+	value := env.GetProperty(propertyName, "")
+	if value == nil {
+		return values
+	}
+	for _, item := range strings.Split(fmt.Sprint(value), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
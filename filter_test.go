@@ -0,0 +1,140 @@
+package context
+
+import (
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPackageFilter(t *testing.T) {
+	componentType := goo.GetType(newTestLazyComponent)
+
+	filter, err := NewPackageFilter("github.com/procyon-projects/*")
+	assert.Nil(t, err)
+	assert.True(t, filter.Matches("testLazyComponent", componentType))
+
+	filter, err = NewPackageFilter("github.com/**")
+	assert.Nil(t, err)
+	assert.True(t, filter.Matches("testLazyComponent", componentType))
+
+	filter, err = NewPackageFilter("github.com/*")
+	assert.Nil(t, err)
+	assert.False(t, filter.Matches("testLazyComponent", componentType))
+
+	filter, err = NewPackageFilter("github.com/procyon-projects/procyon-?ontext")
+	assert.Nil(t, err)
+	assert.True(t, filter.Matches("testLazyComponent", componentType))
+
+	_, err = NewPackageFilter(" ")
+	assert.NotNil(t, err)
+}
+
+func TestTypeNameFilter(t *testing.T) {
+	filter, err := NewTypeNameFilter("Lazy")
+	assert.Nil(t, err)
+	assert.True(t, filter.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+	assert.False(t, filter.Matches("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	filter, err = NewTypeNameFilter("^github.com/procyon-projects/procyon-context\\.test")
+	assert.Nil(t, err)
+	assert.True(t, filter.Matches("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	_, err = NewTypeNameFilter("(")
+	assert.NotNil(t, err)
+}
+
+func TestAssignableTypeFilter(t *testing.T) {
+	filter := NewAssignableTypeFilter(goo.GetType((*LazyInitComponent)(nil)))
+	assert.True(t, filter.Matches("testEagerComponent", goo.GetType(newTestEagerComponent)))
+	assert.False(t, filter.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+
+	filter = NewAssignableTypeFilter(goo.GetType(testLazyComponent{}))
+	assert.True(t, filter.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+	assert.False(t, filter.Matches("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	assert.Panics(t, func() {
+		NewAssignableTypeFilter(nil)
+	})
+}
+
+func TestComponentFilters(t *testing.T) {
+	filters := NewComponentFilters()
+	assert.True(t, filters.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+
+	filters.AddIncludeFilter(ComponentFilterFunc(func(componentName string, componentType goo.Type) bool {
+		return componentName != "testEagerComponent"
+	}))
+	assert.True(t, filters.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+	assert.False(t, filters.Matches("testEagerComponent", goo.GetType(newTestEagerComponent)))
+
+	filters.AddExcludeFilter(NewAssignableTypeFilter(goo.GetType(testLazyComponent{})))
+	assert.False(t, filters.Matches("testLazyComponent", goo.GetType(newTestLazyComponent)))
+
+	assert.Panics(t, func() {
+		filters.AddIncludeFilter(nil)
+	})
+	assert.Panics(t, func() {
+		filters.AddExcludeFilter(nil)
+	})
+}
+
+func TestComponentPeaDefinitionScanner_ScanComponentsWithFilters(t *testing.T) {
+	components := map[string]goo.Type{
+		"testLazyComponent":  goo.GetType(newTestLazyComponent),
+		"testEagerComponent": goo.GetType(newTestEagerComponent),
+	}
+
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	scanner := NewComponentPeaDefinitionScanner(registry, core.NewStandardEnvironment())
+	scanner.GetComponentFilters().AddExcludeFilter(NewAssignableTypeFilter(goo.GetType((*LazyInitComponent)(nil))))
+	err := scanner.scanComponents(components)
+	assert.Nil(t, err)
+	assert.True(t, registry.ContainsPeaDefinition("testLazyComponent"))
+	assert.False(t, registry.ContainsPeaDefinition("testEagerComponent"))
+
+	registry = peas.NewDefaultPeaDefinitionRegistry()
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--" + ComponentScanIncludePackagesProperty + "=github.com/procyon-projects/**",
+		"--" + ComponentScanExcludeTypesProperty + "=Lazy",
+	}))
+	scanner = NewComponentPeaDefinitionScanner(registry, env)
+	err = scanner.scanComponents(components)
+	assert.Nil(t, err)
+	assert.False(t, registry.ContainsPeaDefinition("testLazyComponent"))
+	assert.True(t, registry.ContainsPeaDefinition("testEagerComponent"))
+
+	env = core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--" + ComponentScanIncludeTypesProperty + "=(",
+	}))
+	scanner = NewComponentPeaDefinitionScanner(peas.NewDefaultPeaDefinitionRegistry(), env)
+	assert.NotNil(t, scanner.scanComponents(components))
+}
+
+func TestComponentPeaDefinitionScanner_ScanContextComponentsWithIncludeFilters(t *testing.T) {
+	components := map[string]goo.Type{
+		"bindingProcessor":   goo.GetType(NewConfigurationPropertiesBindingProcessor),
+		"testEagerComponent": goo.GetType(newTestEagerComponent),
+	}
+	bindingProcessorType := goo.GetType(ConfigurationPropertiesBindingProcessor{})
+
+	registry := peas.NewDefaultPeaDefinitionRegistry()
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--" + ComponentScanIncludePackagesProperty + "=example.com/plugin/**",
+	}))
+	scanner := NewComponentPeaDefinitionScanner(registry, env)
+	assert.Nil(t, scanner.scanComponents(components))
+	assert.Equal(t, 1, len(registry.GetPeaNamesByType(bindingProcessorType)))
+	assert.False(t, registry.ContainsPeaDefinition("testEagerComponent"))
+
+	/* the exclude filters are still applied to the components of the context */
+	registry = peas.NewDefaultPeaDefinitionRegistry()
+	scanner = NewComponentPeaDefinitionScanner(registry, env)
+	scanner.GetComponentFilters().AddExcludeFilter(NewAssignableTypeFilter(bindingProcessorType))
+	assert.Nil(t, scanner.scanComponents(components))
+	assert.Equal(t, 0, len(registry.GetPeaNamesByType(bindingProcessorType)))
+}
@@ -0,0 +1,291 @@
+package context
+
+import (
+	"encoding/json"
+	"github.com/procyon-projects/goo"
+	"github.com/procyon-projects/procyon-peas"
+	"sort"
+	"strings"
+)
+
+type DependencyCycleError struct {
+	path []string
+}
+
+func NewDependencyCycleError(path []string) DependencyCycleError {
+	return DependencyCycleError{
+		path,
+	}
+}
+
+func (err DependencyCycleError) GetPath() []string {
+	return err.path
+}
+
+func (err DependencyCycleError) Error() string {
+	return "dependency cycle detected : " + strings.Join(err.path, " -> ")
+}
+
+type MissingDependency struct {
+	PeaName  string `json:"peaName"`
+	TypeName string `json:"typeName"`
+}
+
+type AmbiguousDependency struct {
+	PeaName    string   `json:"peaName"`
+	TypeName   string   `json:"typeName"`
+	Candidates []string `json:"candidates"`
+}
+
+type DependencyNode struct {
+	PeaName      string   `json:"peaName"`
+	TypeName     string   `json:"typeName"`
+	Scope        string   `json:"scope"`
+	Dependencies []string `json:"dependencies"`
+}
+
+type DependencyGraph struct {
+	nodes                 map[string]*DependencyNode
+	missingDependencies   []MissingDependency
+	ambiguousDependencies []AmbiguousDependency
+}
+
+func NewDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{
+		nodes:                 make(map[string]*DependencyNode, 0),
+		missingDependencies:   make([]MissingDependency, 0),
+		ambiguousDependencies: make([]AmbiguousDependency, 0),
+	}
+}
+
+func (graph *DependencyGraph) AddNode(peaName string, typeName string, scope peas.PeaScope) {
+	if peaName == "" {
+		panic("Pea name must not be empty")
+	}
+	if node, ok := graph.nodes[peaName]; ok {
+		node.TypeName = typeName
+		node.Scope = string(scope)
+		return
+	}
+	graph.nodes[peaName] = &DependencyNode{
+		PeaName:      peaName,
+		TypeName:     typeName,
+		Scope:        string(scope),
+		Dependencies: make([]string, 0),
+	}
+}
+
+func (graph *DependencyGraph) AddDependency(peaName string, dependencyName string) {
+	node, ok := graph.nodes[peaName]
+	if !ok {
+		panic("Pea node must be added before its dependencies : " + peaName)
+	}
+	for _, dependency := range node.Dependencies {
+		if dependency == dependencyName {
+			return
+		}
+	}
+	node.Dependencies = append(node.Dependencies, dependencyName)
+}
+
+func (graph *DependencyGraph) AddMissingDependency(peaName string, typeName string) {
+	graph.missingDependencies = append(graph.missingDependencies, MissingDependency{peaName, typeName})
+}
+
+func (graph *DependencyGraph) AddAmbiguousDependency(peaName string, typeName string, candidates []string) {
+	graph.ambiguousDependencies = append(graph.ambiguousDependencies, AmbiguousDependency{peaName, typeName, candidates})
+}
+
+func (graph *DependencyGraph) GetNode(peaName string) (DependencyNode, bool) {
+	if node, ok := graph.nodes[peaName]; ok {
+		return *node, true
+	}
+	return DependencyNode{}, false
+}
+
+func (graph *DependencyGraph) GetNodes() []DependencyNode {
+	nodes := make([]DependencyNode, 0)
+	for _, peaName := range graph.getPeaNames() {
+		nodes = append(nodes, *graph.nodes[peaName])
+	}
+	return nodes
+}
+
+func (graph *DependencyGraph) GetDependencies(peaName string) []string {
+	if node, ok := graph.nodes[peaName]; ok {
+		return node.Dependencies
+	}
+	return []string{}
+}
+
+func (graph *DependencyGraph) GetMissingDependencies() []MissingDependency {
+	return graph.missingDependencies
+}
+
+func (graph *DependencyGraph) GetAmbiguousDependencies() []AmbiguousDependency {
+	return graph.ambiguousDependencies
+}
+
+func (graph *DependencyGraph) getPeaNames() []string {
+	peaNames := make([]string, 0)
+	for peaName := range graph.nodes {
+		peaNames = append(peaNames, peaName)
+	}
+	sort.Strings(peaNames)
+	return peaNames
+}
+
+func (graph *DependencyGraph) FindCycle() []string {
+	/* 0 : not visited, 1 : in progress, 2 : done */
+	states := make(map[string]int, 0)
+	path := make([]string, 0)
+	var visit func(peaName string) []string
+	visit = func(peaName string) []string {
+		states[peaName] = 1
+		path = append(path, peaName)
+		for _, dependency := range graph.GetDependencies(peaName) {
+			if states[dependency] == 1 {
+				for index, name := range path {
+					if name == dependency {
+						cycle := append([]string{}, path[index:]...)
+						return append(cycle, dependency)
+					}
+				}
+			} else if states[dependency] == 0 {
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		states[peaName] = 2
+		return nil
+	}
+	for _, peaName := range graph.getPeaNames() {
+		if states[peaName] == 0 {
+			if cycle := visit(peaName); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+func (graph *DependencyGraph) CheckCycles() error {
+	if cycle := graph.FindCycle(); cycle != nil {
+		return NewDependencyCycleError(cycle)
+	}
+	return nil
+}
+
+func (graph *DependencyGraph) ToDOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph peas {\n")
+	for _, node := range graph.GetNodes() {
+		builder.WriteString("\t\"" + node.PeaName + "\" [label=\"" + node.PeaName + "\\n" + node.TypeName + "\"];\n")
+	}
+	for _, node := range graph.GetNodes() {
+		for _, dependency := range node.Dependencies {
+			builder.WriteString("\t\"" + node.PeaName + "\" -> \"" + dependency + "\";\n")
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func (graph *DependencyGraph) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(struct {
+		Nodes                 []DependencyNode      `json:"nodes"`
+		MissingDependencies   []MissingDependency   `json:"missingDependencies"`
+		AmbiguousDependencies []AmbiguousDependency `json:"ambiguousDependencies"`
+	}{
+		graph.GetNodes(),
+		graph.missingDependencies,
+		graph.ambiguousDependencies,
+	}, "", "  ")
+}
+
+func newDependencyGraph(peaFactory peas.ConfigurablePeaFactory) *DependencyGraph {
+	graph := NewDependencyGraph()
+	peaDefinitionRegistry, ok := peaFactory.(peas.PeaDefinitionRegistry)
+	if !ok {
+		for _, sharedPeaName := range peaFactory.GetSharedPeaNames() {
+			graph.AddNode(sharedPeaName, goo.GetType(peaFactory.GetSharedPea(sharedPeaName)).GetFullName(), peas.SharedScope)
+		}
+		return graph
+	}
+
+	for _, sharedPeaName := range getSharedPeaNamesWithoutDefinition(peaFactory, peaDefinitionRegistry) {
+		graph.AddNode(sharedPeaName, goo.GetType(peaFactory.GetSharedPea(sharedPeaName)).GetFullName(), peas.SharedScope)
+	}
+
+	peaDefinitionNames := peaDefinitionRegistry.GetPeaDefinitionNames()
+	sort.Strings(peaDefinitionNames)
+	for _, peaName := range peaDefinitionNames {
+		peaDefinition := peaDefinitionRegistry.GetPeaDefinition(peaName)
+		graph.AddNode(peaName, getComponentReturnType(peaDefinition.GetPeaType()).GetFullName(), peaDefinition.GetScope())
+	}
+
+	for _, peaName := range peaDefinitionNames {
+		peaType := peaDefinitionRegistry.GetPeaDefinition(peaName).GetPeaType()
+		if !peaType.IsFunction() {
+			continue
+		}
+		for _, parameterType := range peaType.ToFunctionType().GetFunctionParameterTypes() {
+			if !parameterType.IsStruct() && !parameterType.IsInterface() {
+				continue
+			}
+			dependencyName, candidateNames := resolveDependencyName(peaFactory, peaDefinitionRegistry, peaName, parameterType)
+			if len(candidateNames) == 0 {
+				graph.AddMissingDependency(peaName, parameterType.GetFullName())
+			} else if dependencyName == "" {
+				graph.AddAmbiguousDependency(peaName, parameterType.GetFullName(), candidateNames)
+			} else {
+				graph.AddDependency(peaName, dependencyName)
+			}
+		}
+	}
+	return graph
+}
+
+func resolveDependencyName(peaFactory peas.ConfigurablePeaFactory, peaDefinitionRegistry peas.PeaDefinitionRegistry,
+	peaName string, parameterType goo.Type) (string, []string) {
+	candidateNames := getDependencyCandidateNames(peaFactory, peaDefinitionRegistry, peaName, parameterType)
+	if len(candidateNames) == 1 {
+		return candidateNames[0], candidateNames
+	}
+	/* the primary pea is preferred if there is more than one candidate */
+	if primaryPeaNames := getPrimaryPeaNames(peaDefinitionRegistry, candidateNames); len(primaryPeaNames) == 1 {
+		return primaryPeaNames[0], candidateNames
+	}
+	return "", candidateNames
+}
+
+func getSharedPeaNamesWithoutDefinition(peaFactory peas.ConfigurablePeaFactory, peaDefinitionRegistry peas.PeaDefinitionRegistry) []string {
+	sharedPeaNames := make([]string, 0)
+	for _, sharedPeaName := range peaFactory.GetSharedPeaNames() {
+		if !peaDefinitionRegistry.ContainsPeaDefinition(sharedPeaName) {
+			sharedPeaNames = append(sharedPeaNames, sharedPeaName)
+		}
+	}
+	return sharedPeaNames
+}
+
+func getDependencyCandidateNames(peaFactory peas.ConfigurablePeaFactory, peaDefinitionRegistry peas.PeaDefinitionRegistry,
+	peaName string, parameterType goo.Type) []string {
+	sharedPeaNames := getSharedPeaNamesWithoutDefinition(peaFactory, peaDefinitionRegistry)
+	candidateNames := make([]string, 0)
+	/* a pea cannot depend on itself, e.g. a decorator taking the interface it implements */
+	for _, candidateName := range peaDefinitionRegistry.GetPeaNamesByType(parameterType) {
+		if candidateName != peaName {
+			candidateNames = append(candidateNames, candidateName)
+		}
+	}
+	for _, sharedPeaName := range sharedPeaNames {
+		if isAssignableType(goo.GetType(peaFactory.GetSharedPea(sharedPeaName)), parameterType) {
+			candidateNames = append(candidateNames, sharedPeaName)
+		}
+	}
+	sort.Strings(candidateNames)
+	return candidateNames
+}
@@ -0,0 +1,171 @@
+package context
+
+import (
+	"encoding/json"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type testCycleA struct {
+}
+
+func newTestCycleA(b testCycleB) testCycleA {
+	return testCycleA{}
+}
+
+type testCycleB struct {
+}
+
+func newTestCycleB(c testCycleC) testCycleB {
+	return testCycleB{}
+}
+
+type testCycleC struct {
+}
+
+func newTestCycleC(a testCycleA) testCycleC {
+	return testCycleC{}
+}
+
+type testGraphService struct {
+}
+
+func newTestGraphService(logger Logger, dataSource testDataSource) testGraphService {
+	return testGraphService{}
+}
+
+type testDataSourceDecorator struct {
+	dataSource testDataSource
+}
+
+func newTestDataSourceDecorator(dataSource testDataSource) testDataSourceDecorator {
+	return testDataSourceDecorator{dataSource}
+}
+
+func (decorator testDataSourceDecorator) GetDataSourceName() string {
+	return "decorated-" + decorator.dataSource.GetDataSourceName()
+}
+
+type testGraphRepository struct {
+}
+
+func newTestGraphRepository(service testGraphService) testGraphRepository {
+	return testGraphRepository{}
+}
+
+func TestDependencyGraph_FindCycle(t *testing.T) {
+	graph := NewDependencyGraph()
+	graph.AddNode("a", "A", peas.SharedScope)
+	graph.AddNode("b", "B", peas.SharedScope)
+	graph.AddNode("c", "C", peas.SharedScope)
+	graph.AddDependency("a", "b")
+	graph.AddDependency("b", "c")
+	assert.Nil(t, graph.FindCycle())
+	assert.Nil(t, graph.CheckCycles())
+
+	graph.AddDependency("c", "b")
+	assert.Equal(t, []string{"b", "c", "b"}, graph.FindCycle())
+	err := graph.CheckCycles()
+	assert.NotNil(t, err)
+	assert.Equal(t, "dependency cycle detected : b -> c -> b", err.Error())
+
+	assert.Panics(t, func() {
+		graph.AddDependency("d", "a")
+	})
+}
+
+func TestDependencyGraph_FromPeaDefinitions(t *testing.T) {
+	var peaFactory peas.ConfigurablePeaFactory = peas.NewDefaultPeaFactory()
+	peaFactory.RegisterSharedPea("logger", NewSimpleLogger())
+	registry := peaFactory.(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("testGraphService", NewScannedPeaDefinition("testGraphService", goo.GetType(newTestGraphService)))
+
+	graph := newDependencyGraph(peaFactory)
+	assert.Equal(t, []string{"logger"}, graph.GetDependencies("testGraphService"))
+	assert.Equal(t, 1, len(graph.GetMissingDependencies()))
+	assert.Equal(t, "testGraphService", graph.GetMissingDependencies()[0].PeaName)
+
+	node, ok := graph.GetNode("testGraphService")
+	assert.True(t, ok)
+	assert.Equal(t, "shared", node.Scope)
+
+	dot := graph.ToDOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph peas {"))
+	assert.True(t, strings.Contains(dot, "\"testGraphService\" -> \"logger\";"))
+
+	data, err := graph.ToJSON()
+	assert.Nil(t, err)
+	result := make(map[string]interface{})
+	assert.Nil(t, json.Unmarshal(data, &result))
+	assert.Equal(t, 1, len(result["missingDependencies"].([]interface{})))
+}
+
+func TestBaseApplicationContext_DependencyCycle(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("testCycleA", NewScannedPeaDefinition("testCycleA", goo.GetType(newTestCycleA)))
+	registry.RegisterPeaDefinition("testCycleB", NewScannedPeaDefinition("testCycleB", goo.GetType(newTestCycleB)))
+	registry.RegisterPeaDefinition("testCycleC", NewScannedPeaDefinition("testCycleC", goo.GetType(newTestCycleC)))
+
+	err := baseApplicationContext.Configure()
+	cycleError, ok := err.(DependencyCycleError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"testCycleA", "testCycleB", "testCycleC", "testCycleA"}, cycleError.GetPath())
+}
+
+func TestDependencyGraph_ResolvesDependenciesLikeTheContext(t *testing.T) {
+	var peaFactory peas.ConfigurablePeaFactory = peas.NewDefaultPeaFactory()
+	registry := peaFactory.(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource)))
+	registry.RegisterPeaDefinition("decorator", NewScannedPeaDefinition("decorator", goo.GetType(newTestDataSourceDecorator)))
+	registry.RegisterPeaDefinition("testGraphRepository", NewScannedPeaDefinition("testGraphRepository", goo.GetType(newTestGraphRepository)))
+
+	graph := newDependencyGraph(peaFactory)
+	assert.Equal(t, []string{"userDataSource"}, graph.GetDependencies("decorator"))
+	assert.Nil(t, graph.FindCycle())
+	assert.Equal(t, []MissingDependency{{"testGraphRepository", goo.GetType(testGraphService{}).GetFullName()}}, graph.GetMissingDependencies())
+
+	registry.RegisterPeaDefinition("defaultDataSource", NewScannedPeaDefinition("defaultDataSource", goo.GetType(newTestDefaultDataSource)))
+	graph = newDependencyGraph(peaFactory)
+	assert.Equal(t, []string{}, graph.GetDependencies("decorator"))
+	assert.Equal(t, 1, len(graph.GetAmbiguousDependencies()))
+	assert.Equal(t, "decorator", graph.GetAmbiguousDependencies()[0].PeaName)
+	assert.Equal(t, []string{"defaultDataSource", "userDataSource"}, graph.GetAmbiguousDependencies()[0].Candidates)
+
+	registry.RemovePeaDefinition("userDataSource")
+	registry.RegisterPeaDefinition("userDataSource", NewScannedPeaDefinition("userDataSource", goo.GetType(newTestUserDataSource), WithPrimary(true)))
+	graph = newDependencyGraph(peaFactory)
+	assert.Equal(t, []string{"userDataSource"}, graph.GetDependencies("decorator"))
+	assert.Equal(t, 0, len(graph.GetAmbiguousDependencies()))
+}
+
+func TestBaseApplicationContext_DependencyGraphMatchesInjection(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("userDS", NewScannedPeaDefinition("userDS", goo.GetType(newTestUserDataSource), WithPrimary(true)))
+	registry.RegisterPeaDefinition("defaultDS", NewScannedPeaDefinition("defaultDS", goo.GetType(newTestDefaultDataSource)))
+	registry.RegisterPeaDefinition("service", NewScannedPeaDefinition("service", goo.GetType(newTestGraphService)))
+	registry.RegisterPeaDefinition("consumer", NewScannedPeaDefinition("consumer", goo.GetType(newTestDataSourceConsumer)))
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	graph := baseApplicationContext.GetDependencyGraph()
+	assert.Equal(t, 0, len(graph.GetAmbiguousDependencies()))
+	assert.Equal(t, []string{"userDS"}, graph.GetDependencies("consumer"))
+	assert.Contains(t, graph.GetDependencies("service"), "userDS")
+
+	/* the injected pea is the one the graph has the edge to */
+	service, err := baseApplicationContext.GetPea("service")
+	assert.Nil(t, err)
+	assert.IsType(t, testGraphService{}, service)
+	consumer, err := baseApplicationContext.GetPea("consumer")
+	assert.Nil(t, err)
+	assert.IsType(t, testUserDataSource{}, consumer.(testDataSourceConsumer).dataSource)
+}
@@ -1,5 +1,49 @@
 package context
 
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"sort"
+)
+
 type ApplicationContextInitializer interface {
 	InitializeContext(context ConfigurableApplicationContext)
 }
+
+func getComponentInitializers() ([]ApplicationContextInitializer, error) {
+	initializerType := goo.GetType((*ApplicationContextInitializer)(nil))
+	initializerTypes, err := core.GetComponentTypes(initializerType)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(initializerTypes, func(i, j int) bool {
+		return initializerTypes[i].GetFullName() < initializerTypes[j].GetFullName()
+	})
+	initializers := make([]ApplicationContextInitializer, 0)
+	for _, typ := range initializerTypes {
+		if typ.ToFunctionType().GetFunctionParameterCount() != 0 {
+			return nil, errors.New("initializer constructor functions must not have any parameter : " + typ.GetFullName())
+		}
+		instance, err := peas.CreateInstance(typ, nil)
+		if err != nil {
+			return nil, err
+		}
+		initializers = append(initializers, instance.(ApplicationContextInitializer))
+	}
+	return initializers, nil
+}
+
+func sortInitializers(initializers []ApplicationContextInitializer) []ApplicationContextInitializer {
+	instances := make([]interface{}, len(initializers))
+	for index, initializer := range initializers {
+		instances[index] = initializer
+	}
+	sortByOrder(instances)
+	sorted := make([]ApplicationContextInitializer, len(instances))
+	for index, instance := range instances {
+		sorted[index] = instance.(ApplicationContextInitializer)
+	}
+	return sorted
+}
@@ -0,0 +1,214 @@
+package context
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type PropertyDeprecation struct {
+	Reason      string `json:"reason,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type PropertyMetadata struct {
+	Name         string               `json:"name"`
+	Type         string               `json:"type"`
+	SourceType   string               `json:"sourceType"`
+	DefaultValue string               `json:"defaultValue,omitempty"`
+	Description  string               `json:"description,omitempty"`
+	Required     bool                 `json:"required,omitempty"`
+	Deprecation  *PropertyDeprecation `json:"deprecation,omitempty"`
+}
+
+type PropertyGroupMetadata struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	SourceType  string `json:"sourceType"`
+	Description string `json:"description,omitempty"`
+}
+
+type ConfigurationMetadata struct {
+	groups     []PropertyGroupMetadata
+	properties []PropertyMetadata
+}
+
+func NewConfigurationMetadata() *ConfigurationMetadata {
+	return &ConfigurationMetadata{
+		groups:     make([]PropertyGroupMetadata, 0),
+		properties: make([]PropertyMetadata, 0),
+	}
+}
+
+func GenerateConfigurationMetadata() (*ConfigurationMetadata, error) {
+	propertiesTypes, err := core.GetComponentTypes(goo.GetType((*ConfigurationProperties)(nil)))
+	if err != nil {
+		return nil, err
+	}
+	metadata := NewConfigurationMetadata()
+	for _, propertiesType := range propertiesTypes {
+		returnType := propertiesType.ToFunctionType().GetFunctionReturnTypes()[0].GetGoType()
+		if returnType.Kind() == reflect.Ptr {
+			returnType = returnType.Elem()
+		}
+		properties, ok := reflect.New(returnType).Interface().(ConfigurationProperties)
+		if !ok {
+			continue
+		}
+		if err = metadata.Add(properties); err != nil {
+			return nil, err
+		}
+	}
+	return metadata, nil
+}
+
+func (metadata *ConfigurationMetadata) Add(properties ConfigurationProperties) error {
+	if properties == nil {
+		return errors.New("properties must not be null")
+	}
+	prefix := properties.GetConfigurationPrefix()
+	if prefix == "" {
+		return errors.New("prefix must not be null")
+	}
+	typ := reflect.TypeOf(properties)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return errors.New("configuration properties must be a struct : " + typ.String())
+	}
+	prefix = GetCanonicalPropertyName(prefix)
+	metadata.addGroup(PropertyGroupMetadata{prefix, getMetadataTypeName(typ), getMetadataTypeName(typ), ""})
+	metadata.addFields(prefix, typ, map[reflect.Type]bool{typ: true})
+	sort.SliceStable(metadata.groups, func(i, j int) bool {
+		return metadata.groups[i].Name < metadata.groups[j].Name
+	})
+	sort.SliceStable(metadata.properties, func(i, j int) bool {
+		return metadata.properties[i].Name < metadata.properties[j].Name
+	})
+	return nil
+}
+
+func (metadata *ConfigurationMetadata) GetGroups() []PropertyGroupMetadata {
+	return metadata.groups
+}
+
+func (metadata *ConfigurationMetadata) GetProperties() []PropertyMetadata {
+	return metadata.properties
+}
+
+func (metadata *ConfigurationMetadata) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(struct {
+		Groups     []PropertyGroupMetadata `json:"groups"`
+		Properties []PropertyMetadata      `json:"properties"`
+	}{
+		metadata.groups,
+		metadata.properties,
+	}, "", "  ")
+}
+
+func (metadata *ConfigurationMetadata) addGroup(group PropertyGroupMetadata) {
+	for _, existingGroup := range metadata.groups {
+		if existingGroup.Name == group.Name {
+			return
+		}
+	}
+	metadata.groups = append(metadata.groups, group)
+}
+
+func (metadata *ConfigurationMetadata) addProperty(property PropertyMetadata) {
+	for _, existingProperty := range metadata.properties {
+		if existingProperty.Name == property.Name {
+			return
+		}
+	}
+	metadata.properties = append(metadata.properties, property)
+}
+
+func (metadata *ConfigurationMetadata) addFields(prefix string, typ reflect.Type, visitedTypes map[reflect.Type]bool) {
+	binder := ConfigurationPropertiesBinder{}
+	structValue := reflect.New(typ)
+	for _, field := range goo.GetType(structValue.Interface()).ToStructType().GetExportedFields() {
+		tag, ok := binder.getPropertyTag(field)
+		if !ok {
+			continue
+		}
+		propertyName := binder.getFullPropertyName(prefix, tag.GetName())
+		fieldType := structValue.Elem().FieldByName(field.GetName()).Type()
+		description := binder.getTagValue(field, "desc")
+
+		/* nested properties are described by their own names, collections and maps by using wildcards */
+		nestedName, nestedType := propertyName, fieldType
+		switch {
+		case nestedType.Kind() == reflect.Slice || nestedType.Kind() == reflect.Array:
+			if !isValueType(nestedType) {
+				nestedName, nestedType = propertyName+"[*]", nestedType.Elem()
+			}
+		case nestedType.Kind() == reflect.Map:
+			nestedName, nestedType = propertyName+".*", nestedType.Elem()
+		}
+		if nestedType.Kind() == reflect.Ptr {
+			nestedType = nestedType.Elem()
+		}
+		if binder.isNestedStructType(nestedType) {
+			if visitedTypes[nestedType] {
+				continue
+			}
+			metadata.addGroup(PropertyGroupMetadata{nestedName, getMetadataTypeName(nestedType), getMetadataTypeName(typ), description})
+			visitedTypes[nestedType] = true
+			metadata.addFields(nestedName, nestedType, visitedTypes)
+			delete(visitedTypes, nestedType)
+			continue
+		}
+
+		metadata.addProperty(PropertyMetadata{
+			Name:         propertyName,
+			Type:         getMetadataTypeName(fieldType),
+			SourceType:   getMetadataTypeName(typ),
+			DefaultValue: binder.getTagValue(field, "default"),
+			Description:  description,
+			Required:     binder.isRequiredField(field),
+			Deprecation:  getPropertyDeprecation(binder, field),
+		})
+	}
+}
+
+func getPropertyDeprecation(binder ConfigurationPropertiesBinder, field goo.Field) *PropertyDeprecation {
+	deprecatedTag, err := field.GetTagByName("deprecated")
+	if err != nil {
+		return nil
+	}
+	reason := strings.TrimSpace(deprecatedTag.Value)
+	if reason == "false" {
+		return nil
+	} else if reason == "true" {
+		reason = ""
+	}
+	return &PropertyDeprecation{
+		Reason:      reason,
+		Replacement: strings.TrimSpace(binder.getTagValue(field, "replacement")),
+	}
+}
+
+func getMetadataTypeName(typ reflect.Type) string {
+	/* the named types are given by their package paths, also inside composite types */
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return "*" + getMetadataTypeName(typ.Elem())
+	case reflect.Slice:
+		return "[]" + getMetadataTypeName(typ.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(typ.Len()) + "]" + getMetadataTypeName(typ.Elem())
+	case reflect.Map:
+		return "map[" + getMetadataTypeName(typ.Key()) + "]" + getMetadataTypeName(typ.Elem())
+	}
+	if typ.PkgPath() == "" || typ.Name() == "" {
+		return typ.String()
+	}
+	return typ.PkgPath() + "." + typ.Name()
+}
@@ -0,0 +1,128 @@
+package context
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type testMetadataEndpoint struct {
+	Host string `property:"host" default:"localhost" desc:"Host of the endpoint"`
+	Port int    `property:"port" required:"true"`
+}
+
+type testMetadataNode struct {
+	Name     string            `property:"name"`
+	Children *testMetadataNode `property:"children"`
+}
+
+type testMetadataProperties struct {
+	ServerName string                          `property:"serverName" default:"procyon" desc:"Name of the server"`
+	Timeout    time.Duration                   `property:"timeout" default:"30s" deprecated:"use read-timeout instead" replacement:"app.read-timeout"`
+	MaxSize    int                             `property:"maxSize" deprecated:"true"`
+	Tags       []string                        `property:"tags"`
+	Primary    testMetadataEndpoint            `property:"primary" desc:"Primary endpoint"`
+	Replicas   []testMetadataEndpoint          `property:"replicas"`
+	Clusters   map[string]testMetadataEndpoint `property:"clusters"`
+	Root       *testMetadataNode               `property:"root"`
+	MaxBody    DataSize                        `property:"maxBody"`
+	Limits     map[string]*DataSize            `property:"limits"`
+	Ignored    string                          `property:"-"`
+}
+
+func (properties *testMetadataProperties) GetConfigurationPrefix() string {
+	return "app"
+}
+
+func TestConfigurationMetadata_Add(t *testing.T) {
+	metadata := NewConfigurationMetadata()
+	assert.Nil(t, metadata.Add(&testMetadataProperties{}))
+
+	properties := make(map[string]PropertyMetadata)
+	names := make([]string, 0)
+	for _, property := range metadata.GetProperties() {
+		properties[property.Name] = property
+		names = append(names, property.Name)
+	}
+	assert.Equal(t, []string{
+		"app.clusters.*.host",
+		"app.clusters.*.port",
+		"app.limits",
+		"app.max-body",
+		"app.max-size",
+		"app.primary.host",
+		"app.primary.port",
+		"app.replicas[*].host",
+		"app.replicas[*].port",
+		"app.root.name",
+		"app.server-name",
+		"app.tags",
+		"app.timeout",
+	}, names)
+
+	serverName := properties["app.server-name"]
+	assert.Equal(t, "string", serverName.Type)
+	assert.Equal(t, "procyon", serverName.DefaultValue)
+	assert.Equal(t, "Name of the server", serverName.Description)
+	assert.Equal(t, "github.com/procyon-projects/procyon-context.testMetadataProperties", serverName.SourceType)
+	assert.Nil(t, serverName.Deprecation)
+
+	timeout := properties["app.timeout"]
+	assert.Equal(t, "time.Duration", timeout.Type)
+	assert.Equal(t, &PropertyDeprecation{"use read-timeout instead", "app.read-timeout"}, timeout.Deprecation)
+	assert.Equal(t, &PropertyDeprecation{}, properties["app.max-size"].Deprecation)
+
+	assert.Equal(t, "[]string", properties["app.tags"].Type)
+	/* the types of other packages are given by their package paths as well */
+	assert.Equal(t, "github.com/procyon-projects/procyon-context.DataSize", properties["app.max-body"].Type)
+	assert.Equal(t, "map[string]*github.com/procyon-projects/procyon-context.DataSize", properties["app.limits"].Type)
+	assert.True(t, properties["app.primary.port"].Required)
+	assert.Equal(t, "Host of the endpoint", properties["app.primary.host"].Description)
+	assert.Equal(t, "github.com/procyon-projects/procyon-context.testMetadataEndpoint", properties["app.primary.host"].SourceType)
+
+	groups := make([]string, 0)
+	for _, group := range metadata.GetGroups() {
+		groups = append(groups, group.Name)
+	}
+	assert.Equal(t, []string{"app", "app.clusters.*", "app.primary", "app.replicas[*]", "app.root"}, groups)
+	assert.Equal(t, "Primary endpoint", metadata.GetGroups()[2].Description)
+	for _, group := range metadata.GetGroups()[1:4] {
+		assert.Equal(t, "github.com/procyon-projects/procyon-context.testMetadataEndpoint", group.Type)
+	}
+	assert.Equal(t, "github.com/procyon-projects/procyon-context.testMetadataNode", metadata.GetGroups()[4].Type)
+}
+
+func TestConfigurationMetadata_AddWithInvalidProperties(t *testing.T) {
+	metadata := NewConfigurationMetadata()
+	assert.NotNil(t, metadata.Add(nil))
+}
+
+func TestConfigurationMetadata_ToJSON(t *testing.T) {
+	metadata := NewConfigurationMetadata()
+	assert.Nil(t, metadata.Add(&testMetadataProperties{}))
+
+	data, err := metadata.ToJSON()
+	assert.Nil(t, err)
+
+	var result map[string][]map[string]interface{}
+	assert.Nil(t, json.Unmarshal(data, &result))
+	assert.Equal(t, len(metadata.GetGroups()), len(result["groups"]))
+	assert.Equal(t, len(metadata.GetProperties()), len(result["properties"]))
+
+	timeout := result["properties"][12]
+	assert.Equal(t, "app.timeout", timeout["name"])
+	assert.Equal(t, "30s", timeout["defaultValue"])
+	assert.Equal(t, map[string]interface{}{
+		"reason":      "use read-timeout instead",
+		"replacement": "app.read-timeout",
+	}, timeout["deprecation"])
+	_, ok := result["properties"][11]["deprecation"]
+	assert.False(t, ok)
+}
+
+func TestGenerateConfigurationMetadata(t *testing.T) {
+	metadata, err := GenerateConfigurationMetadata()
+	assert.Nil(t, err)
+	assert.NotNil(t, metadata)
+}
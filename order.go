@@ -0,0 +1,54 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"math"
+	"sort"
+)
+
+const (
+	OrderHighest = math.MinInt32
+	OrderLowest  = math.MaxInt32
+)
+
+type Ordered interface {
+	GetOrder() int
+}
+
+func getOrder(instance interface{}) int {
+	if ordered, ok := instance.(Ordered); ok {
+		return ordered.GetOrder()
+	}
+	return OrderLowest
+}
+
+const (
+	priorityOrderGroup = iota
+	orderedOrderGroup
+	unorderedOrderGroup
+)
+
+func getOrderGroup(instance interface{}) int {
+	if _, ok := instance.(core.Priority); ok {
+		return priorityOrderGroup
+	} else if _, ok := instance.(Ordered); ok {
+		return orderedOrderGroup
+	}
+	return unorderedOrderGroup
+}
+
+func sortByOrder(instances []interface{}) {
+	sort.SliceStable(instances, func(i, j int) bool {
+		groupI, groupJ := getOrderGroup(instances[i]), getOrderGroup(instances[j])
+		if groupI != groupJ {
+			return groupI < groupJ
+		}
+		switch groupI {
+		case priorityOrderGroup:
+			return instances[i].(core.Priority).GetPriority() < instances[j].(core.Priority).GetPriority()
+		case orderedOrderGroup:
+			return getOrder(instances[i]) < getOrder(instances[j])
+		}
+		return false
+	})
+}
@@ -0,0 +1,100 @@
+package context
+
+import (
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testPriorityInstance struct {
+	priority core.PriorityValue
+}
+
+func (instance testPriorityInstance) GetPriority() core.PriorityValue {
+	return instance.priority
+}
+
+type testOrderedInstance struct {
+	order int
+}
+
+func (instance testOrderedInstance) GetOrder() int {
+	return instance.order
+}
+
+func TestSortByOrder(t *testing.T) {
+	instances := []interface{}{
+		"unordered",
+		testOrderedInstance{OrderHighest},
+		testPriorityInstance{core.PriorityLowest},
+		testOrderedInstance{1},
+		testPriorityInstance{core.PriorityHighest},
+	}
+	sortByOrder(instances)
+	assert.Equal(t, []interface{}{
+		testPriorityInstance{core.PriorityHighest},
+		testPriorityInstance{core.PriorityLowest},
+		testOrderedInstance{OrderHighest},
+		testOrderedInstance{1},
+		"unordered",
+	}, instances)
+}
+
+var testInvokedRegistryProcessors = make([]string, 0)
+
+type testFirstRegistryProcessor struct {
+}
+
+func newTestFirstRegistryProcessor() testFirstRegistryProcessor {
+	return testFirstRegistryProcessor{}
+}
+
+func (processor testFirstRegistryProcessor) GetPriority() core.PriorityValue {
+	return core.PriorityHighest
+}
+
+func (processor testFirstRegistryProcessor) AfterPeaDefinitionRegistryInitialization(registry peas.PeaDefinitionRegistry) {
+	testInvokedRegistryProcessors = append(testInvokedRegistryProcessors, "first")
+	registry.RegisterPeaDefinition("testLateRegistryProcessor", peas.NewSimplePeaDefinition(goo.GetType(newTestLateRegistryProcessor)))
+}
+
+type testSecondRegistryProcessor struct {
+}
+
+func newTestSecondRegistryProcessor() testSecondRegistryProcessor {
+	return testSecondRegistryProcessor{}
+}
+
+func (processor testSecondRegistryProcessor) GetOrder() int {
+	return OrderHighest
+}
+
+func (processor testSecondRegistryProcessor) AfterPeaDefinitionRegistryInitialization(registry peas.PeaDefinitionRegistry) {
+	testInvokedRegistryProcessors = append(testInvokedRegistryProcessors, "second")
+}
+
+type testLateRegistryProcessor struct {
+}
+
+func newTestLateRegistryProcessor() testLateRegistryProcessor {
+	return testLateRegistryProcessor{}
+}
+
+func (processor testLateRegistryProcessor) AfterPeaDefinitionRegistryInitialization(registry peas.PeaDefinitionRegistry) {
+	testInvokedRegistryProcessors = append(testInvokedRegistryProcessors, "late")
+}
+
+func TestBaseApplicationContext_OrderedRegistryProcessors(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("aSecondRegistryProcessor", peas.NewSimplePeaDefinition(goo.GetType(newTestSecondRegistryProcessor)))
+	registry.RegisterPeaDefinition("bFirstRegistryProcessor", peas.NewSimplePeaDefinition(goo.GetType(newTestFirstRegistryProcessor)))
+
+	testInvokedRegistryProcessors = make([]string, 0)
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.Equal(t, []string{"first", "second", "late"}, testInvokedRegistryProcessors)
+}
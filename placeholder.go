@@ -0,0 +1,158 @@
+package context
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	placeholderPrefix    = "${"
+	placeholderSuffix    = "}"
+	placeholderSeparator = ":"
+	placeholderEscape    = '\\'
+)
+
+type PlaceholderCycleError struct {
+	chain []string
+}
+
+func NewPlaceholderCycleError(chain []string) PlaceholderCycleError {
+	return PlaceholderCycleError{
+		chain,
+	}
+}
+
+func (err PlaceholderCycleError) GetChain() []string {
+	return err.chain
+}
+
+func (err PlaceholderCycleError) Error() string {
+	return "circular placeholder reference : " + strings.Join(err.chain, " -> ")
+}
+
+type UnresolvablePlaceholderError struct {
+	placeholderName string
+	value           string
+}
+
+func NewUnresolvablePlaceholderError(placeholderName string, value string) UnresolvablePlaceholderError {
+	return UnresolvablePlaceholderError{
+		placeholderName,
+		value,
+	}
+}
+
+func (err UnresolvablePlaceholderError) GetPlaceholderName() string {
+	return err.placeholderName
+}
+
+func (err UnresolvablePlaceholderError) GetValue() string {
+	return err.value
+}
+
+func (err UnresolvablePlaceholderError) Error() string {
+	return "placeholder '" + err.placeholderName + "' could not be resolved in the value '" + err.value + "'"
+}
+
+type PlaceholderResolver struct {
+	lookup func(name string) (string, bool)
+}
+
+func NewPlaceholderResolver(lookup func(name string) (string, bool)) PlaceholderResolver {
+	if lookup == nil {
+		panic("Lookup function must not be null")
+	}
+	return PlaceholderResolver{
+		lookup,
+	}
+}
+
+func (resolver PlaceholderResolver) Resolve(value string) (string, error) {
+	return resolver.resolve(value, make([]string, 0))
+}
+
+func (resolver PlaceholderResolver) resolve(value string, chain []string) (string, error) {
+	var builder strings.Builder
+	for index := 0; index < len(value); {
+		/* escaped placeholders, e.g. \${name}, are kept as they are without the escape character */
+		if value[index] == placeholderEscape && strings.HasPrefix(value[index+1:], placeholderPrefix) {
+			builder.WriteString(placeholderPrefix)
+			index += 1 + len(placeholderPrefix)
+			continue
+		}
+		if !strings.HasPrefix(value[index:], placeholderPrefix) {
+			builder.WriteByte(value[index])
+			index++
+			continue
+		}
+		end := resolver.findPlaceholderEnd(value, index+len(placeholderPrefix))
+		if end < 0 {
+			return "", errors.New("placeholder is not closed in the value '" + value + "'")
+		}
+		resolvedValue, err := resolver.resolvePlaceholder(value, value[index+len(placeholderPrefix):end], chain)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(resolvedValue)
+		index = end + len(placeholderSuffix)
+	}
+	return builder.String(), nil
+}
+
+func (resolver PlaceholderResolver) resolvePlaceholder(value string, placeholder string, chain []string) (string, error) {
+	name, defaultValue, hasDefault := placeholder, "", false
+	if separatorIndex := resolver.findSeparator(placeholder); separatorIndex > -1 {
+		name, defaultValue, hasDefault = placeholder[:separatorIndex], placeholder[separatorIndex+len(placeholderSeparator):], true
+	}
+	/* the names of placeholders might also contain placeholders */
+	name, err := resolver.resolve(name, chain)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSpace(name)
+	for _, chainName := range chain {
+		if chainName == name {
+			return "", NewPlaceholderCycleError(append(append([]string{}, chain...), name))
+		}
+	}
+	if propertyValue, ok := resolver.lookup(name); ok {
+		return resolver.resolve(propertyValue, append(append([]string{}, chain...), name))
+	}
+	if hasDefault {
+		return resolver.resolve(defaultValue, chain)
+	}
+	return "", NewUnresolvablePlaceholderError(name, value)
+}
+
+func (resolver PlaceholderResolver) findPlaceholderEnd(value string, start int) int {
+	depth := 1
+	for index := start; index < len(value); index++ {
+		if value[index] == placeholderEscape && strings.HasPrefix(value[index+1:], placeholderPrefix) {
+			index += len(placeholderPrefix)
+		} else if strings.HasPrefix(value[index:], placeholderPrefix) {
+			depth++
+			index += len(placeholderPrefix) - 1
+		} else if strings.HasPrefix(value[index:], placeholderSuffix) {
+			depth--
+			if depth == 0 {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+func (resolver PlaceholderResolver) findSeparator(placeholder string) int {
+	depth := 0
+	for index := 0; index < len(placeholder); index++ {
+		if strings.HasPrefix(placeholder[index:], placeholderPrefix) {
+			depth++
+			index += len(placeholderPrefix) - 1
+		} else if strings.HasPrefix(placeholder[index:], placeholderSuffix) {
+			depth--
+		} else if depth == 0 && strings.HasPrefix(placeholder[index:], placeholderSeparator) {
+			return index
+		}
+	}
+	return -1
+}
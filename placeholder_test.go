@@ -0,0 +1,114 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestPlaceholderResolver(properties map[string]string) PlaceholderResolver {
+	return NewPlaceholderResolver(func(name string) (string, bool) {
+		value, ok := properties[name]
+		return value, ok
+	})
+}
+
+func TestNewPlaceholderResolver_WithNilLookup(t *testing.T) {
+	assert.Panics(t, func() {
+		NewPlaceholderResolver(nil)
+	})
+}
+
+func TestPlaceholderResolver_Resolve(t *testing.T) {
+	resolver := newTestPlaceholderResolver(map[string]string{
+		"DB_HOST":   "db.local",
+		"host":      "${DB_HOST}",
+		"name":      "HOST",
+		"empty":     "",
+		"db.prefix": "jdbc",
+	})
+
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"plain value", "plain value"},
+		{"${DB_HOST:localhost}:${DB_PORT:5432}", "db.local:5432"},
+		{"${host}", "db.local"},
+		{"${DB_${name}}", "db.local"},
+		{"${MISSING:${DB_PORT:5432}}", "5432"},
+		{"${MISSING:a:b}", "a:b"},
+		{"${MISSING:}", ""},
+		{"${empty:default}", ""},
+		{"${db.prefix}://${host}", "jdbc://db.local"},
+		{"\\${host}", "${host}"},
+		{"${MISSING:\\${host}}", "${host}"},
+		{"{host}$", "{host}$"},
+	}
+	for _, testCase := range testCases {
+		resolvedValue, err := resolver.Resolve(testCase.value)
+		assert.Nil(t, err, testCase.value)
+		assert.Equal(t, testCase.expected, resolvedValue, testCase.value)
+	}
+}
+
+func TestPlaceholderResolver_ResolveWithErrors(t *testing.T) {
+	resolver := newTestPlaceholderResolver(map[string]string{
+		"a":    "${b}",
+		"b":    "prefix-${c}",
+		"c":    "${a}",
+		"self": "${self:default}",
+	})
+
+	_, err := resolver.Resolve("value-${a}")
+	assert.NotNil(t, err)
+	cycleError, ok := err.(PlaceholderCycleError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b", "c", "a"}, cycleError.GetChain())
+	assert.Equal(t, "circular placeholder reference : a -> b -> c -> a", err.Error())
+
+	_, err = resolver.Resolve("${self}")
+	assert.Equal(t, []string{"self", "self"}, err.(PlaceholderCycleError).GetChain())
+
+	_, err = resolver.Resolve("${missing}")
+	unresolvableError, ok := err.(UnresolvablePlaceholderError)
+	assert.True(t, ok)
+	assert.Equal(t, "missing", unresolvableError.GetPlaceholderName())
+	assert.Equal(t, "${missing}", unresolvableError.GetValue())
+
+	_, err = resolver.Resolve("${missing")
+	assert.Equal(t, "placeholder is not closed in the value '${missing'", err.Error())
+}
+
+type testPlaceholderProperties struct {
+	Url     string            `json:"url" default:"${app.host:localhost}:${app.port:5432}"`
+	Port    int               `json:"port" default:"${app.port:5432}"`
+	Tags    []string          `json:"tags" default:"${app.tag},b"`
+	Labels  map[string]string `json:"labels" default:"env=${app.env:test}"`
+	Invalid string            `json:"invalid"`
+}
+
+func (testPlaceholderProperties) GetConfigurationPrefix() string {
+	return "placeholder"
+}
+
+func TestConfigurationPropertiesBinder_BindWithPlaceholders(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.port=6543", "--app.tag=a", "--app.env=prod", "--placeholder.invalid=${app.a}",
+		"--app.a=${app.b}", "--app.b=${app.a}"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testPlaceholderProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	assert.NotNil(t, err)
+	failures := err.(BindError).GetFailures()
+	assert.Equal(t, 1, len(failures))
+	assert.Equal(t, "placeholder.invalid", failures[0].GetPropertyName())
+	assert.Equal(t, "circular placeholder reference : app.a -> app.b -> app.a", failures[0].GetCause().Error())
+
+	assert.Equal(t, "localhost:6543", properties.Url)
+	assert.Equal(t, 6543, properties.Port)
+	assert.Equal(t, []string{"a", "b"}, properties.Tags)
+	assert.Equal(t, map[string]string{"env": "prod"}, properties.Labels)
+}
@@ -6,10 +6,13 @@ import (
 )
 
 type BootstrapProcessor struct {
+	env core.Environment
 }
 
-func NewBootstrapProcessor() BootstrapProcessor {
-	return BootstrapProcessor{}
+func NewBootstrapProcessor(env core.Environment) BootstrapProcessor {
+	return BootstrapProcessor{
+		env,
+	}
 }
 
 func (processor BootstrapProcessor) AfterPeaDefinitionRegistryInitialization(registry peas.PeaDefinitionRegistry) {
@@ -21,8 +24,23 @@ func (processor BootstrapProcessor) AfterPeaFactoryInitialization(factory peas.C
 }
 
 func (processor BootstrapProcessor) processPeaDefinitions(registry peas.PeaDefinitionRegistry) {
-	scanner := NewComponentPeaDefinitionScanner(registry)
-	scanner.DoScan()
+	scanner := NewComponentPeaDefinitionScanner(registry, processor.env)
+	sharedPeaRegistry, isSharedPeaRegistry := registry.(peas.SharedPeaRegistry)
+	if isSharedPeaRegistry {
+		if filters, ok := sharedPeaRegistry.GetSharedPea(componentScanFilters).(*ComponentFilters); ok {
+			scanner.GetComponentFilters().AddAll(filters)
+		}
+	}
+	err := scanner.DoScan()
+	if err != nil {
+		panic(err)
+	}
+	if isSharedPeaRegistry && !sharedPeaRegistry.ContainsSharedPea(conditionEvaluationReport) {
+		err = sharedPeaRegistry.RegisterSharedPea(conditionEvaluationReport, scanner.GetConditionEvaluationReport())
+		if err != nil {
+			panic(err)
+		}
+	}
 }
 
 type EventListenerProcessor struct {
@@ -13,7 +13,7 @@ func TestBootstrapProcessor(t *testing.T) {
 	mockRegistry.On("ContainsPeaDefinition", mock.AnythingOfType("string")).Return(false)
 	mockRegistry.On("RegisterPeaDefinition", mock.AnythingOfType("string"), mock.AnythingOfType("context.ScannedPeaDefinition")).Return(false)
 
-	bootstrapProcessor := NewBootstrapProcessor()
+	bootstrapProcessor := NewBootstrapProcessor(core.NewStandardEnvironment())
 	bootstrapProcessor.processPeaDefinitions(mockRegistry)
 
 	mockRegistry.AssertExpectations(t)
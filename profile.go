@@ -0,0 +1,196 @@
+package context
+
+import (
+	"errors"
+	core "github.com/procyon-projects/procyon-core"
+	"strings"
+	"unicode"
+)
+
+const ActiveProfilesProperty = "procyon.profiles.active"
+
+type ProfileComponent interface {
+	GetProfiles() []string
+}
+
+type Profiles interface {
+	Matches(isActive func(profile string) bool) bool
+}
+
+func GetActiveProfiles(env core.Environment) []string {
+	if env == nil {
+		return make([]string, 0)
+	}
+	return getPropertyValues(env, ActiveProfilesProperty)
+}
+
+func AcceptsProfiles(env core.Environment, expressions ...string) (bool, error) {
+	profiles, err := ParseProfiles(expressions...)
+	if err != nil {
+		return false, err
+	}
+	activeProfiles := GetActiveProfiles(env)
+	return profiles.Matches(func(profile string) bool {
+		for _, activeProfile := range activeProfiles {
+			if activeProfile == profile {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func ParseProfiles(expressions ...string) (Profiles, error) {
+	parsedProfiles := make([]Profiles, 0)
+	for _, expression := range expressions {
+		if strings.TrimSpace(expression) == "" {
+			return nil, errors.New("profile expression must not be empty")
+		}
+		parser := &profileParser{expression: expression, tokens: tokenizeProfileExpression(expression)}
+		profiles, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if parser.position != len(parser.tokens) {
+			return nil, errors.New("malformed profile expression : " + expression)
+		}
+		parsedProfiles = append(parsedProfiles, profiles)
+	}
+	return anyProfiles(parsedProfiles), nil
+}
+
+type profileFunc func(isActive func(profile string) bool) bool
+
+func (fun profileFunc) Matches(isActive func(profile string) bool) bool {
+	return fun(isActive)
+}
+
+func anyProfiles(profiles []Profiles) Profiles {
+	return profileFunc(func(isActive func(profile string) bool) bool {
+		if len(profiles) == 0 {
+			return true
+		}
+		for _, profile := range profiles {
+			if profile.Matches(isActive) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func tokenizeProfileExpression(expression string) []string {
+	tokens := make([]string, 0)
+	current := strings.Builder{}
+	flush := func() {
+		if current.Len() != 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+	for _, character := range expression {
+		switch {
+		case character == '!' || character == '&' || character == '|' || character == '(' || character == ')':
+			flush()
+			tokens = append(tokens, string(character))
+		case unicode.IsSpace(character):
+			flush()
+		default:
+			current.WriteRune(character)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type profileParser struct {
+	expression string
+	tokens     []string
+	position   int
+}
+
+func (parser *profileParser) peek() string {
+	if parser.position < len(parser.tokens) {
+		return parser.tokens[parser.position]
+	}
+	return ""
+}
+
+func (parser *profileParser) parseOr() (Profiles, error) {
+	operands := make([]Profiles, 0)
+	for {
+		operand, err := parser.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if parser.peek() != "|" {
+			break
+		}
+		parser.position++
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return anyProfiles(operands), nil
+}
+
+func (parser *profileParser) parseAnd() (Profiles, error) {
+	operands := make([]Profiles, 0)
+	for {
+		operand, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if parser.peek() != "&" {
+			break
+		}
+		parser.position++
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return profileFunc(func(isActive func(profile string) bool) bool {
+		for _, operand := range operands {
+			if !operand.Matches(isActive) {
+				return false
+			}
+		}
+		return true
+	}), nil
+}
+
+func (parser *profileParser) parseNot() (Profiles, error) {
+	token := parser.peek()
+	switch token {
+	case "":
+		return nil, errors.New("malformed profile expression : " + parser.expression)
+	case "!":
+		parser.position++
+		operand, err := parser.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return profileFunc(func(isActive func(profile string) bool) bool {
+			return !operand.Matches(isActive)
+		}), nil
+	case "(":
+		parser.position++
+		operand, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if parser.peek() != ")" {
+			return nil, errors.New("malformed profile expression : " + parser.expression)
+		}
+		parser.position++
+		return operand, nil
+	case "&", "|", ")":
+		return nil, errors.New("malformed profile expression : " + parser.expression)
+	}
+	parser.position++
+	return profileFunc(func(isActive func(profile string) bool) bool {
+		return isActive(token)
+	}), nil
+}
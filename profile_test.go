@@ -0,0 +1,78 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newTestProfileEnvironment(activeProfiles string) core.StandardEnvironment {
+	env := core.NewStandardEnvironment()
+	env.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app", "--" + ActiveProfilesProperty + "=" + activeProfiles}))
+	return env
+}
+
+func TestGetActiveProfiles(t *testing.T) {
+	assert.Equal(t, []string{}, GetActiveProfiles(nil))
+	assert.Equal(t, []string{"dev", "test"}, GetActiveProfiles(newTestProfileEnvironment("dev, test")))
+}
+
+func TestParseProfiles(t *testing.T) {
+	isActive := func(profiles ...string) func(profile string) bool {
+		return func(profile string) bool {
+			for _, activeProfile := range profiles {
+				if activeProfile == profile {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	testCases := []struct {
+		expression string
+		active     []string
+		matches    bool
+	}{
+		{"dev", []string{"dev"}, true},
+		{"dev", []string{"prod"}, false},
+		{"!dev", []string{"prod"}, true},
+		{"!dev", []string{"dev"}, false},
+		{"dev & cloud", []string{"dev", "cloud"}, true},
+		{"dev & cloud", []string{"dev"}, false},
+		{"dev | test", []string{"test"}, true},
+		{"dev | test", []string{"prod"}, false},
+		{"(dev | test) & !cloud", []string{"test"}, true},
+		{"(dev | test) & !cloud", []string{"test", "cloud"}, false},
+		{"!(dev & cloud)", []string{"dev"}, true},
+	}
+
+	for _, testCase := range testCases {
+		profiles, err := ParseProfiles(testCase.expression)
+		assert.Nil(t, err)
+		assert.Equal(t, testCase.matches, profiles.Matches(isActive(testCase.active...)), testCase.expression)
+	}
+
+	profiles, err := ParseProfiles("dev", "test")
+	assert.Nil(t, err)
+	assert.True(t, profiles.Matches(isActive("test")))
+
+	for _, expression := range []string{"", "dev &", "(dev", "dev)", "& dev", "dev test"} {
+		_, err = ParseProfiles(expression)
+		assert.NotNil(t, err, expression)
+	}
+}
+
+func TestAcceptsProfiles(t *testing.T) {
+	env := newTestProfileEnvironment("dev")
+	accepted, err := AcceptsProfiles(env, "dev | test")
+	assert.Nil(t, err)
+	assert.True(t, accepted)
+
+	accepted, err = AcceptsProfiles(env, "prod")
+	assert.Nil(t, err)
+	assert.False(t, accepted)
+
+	_, err = AcceptsProfiles(env, "prod &")
+	assert.NotNil(t, err)
+}
@@ -3,3 +3,7 @@ package context
 type ConfigurationProperties interface {
 	GetConfigurationPrefix() string
 }
+
+type ValidatableProperties interface {
+	Validate() error
+}
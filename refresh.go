@@ -0,0 +1,369 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	core "github.com/procyon-projects/procyon-core"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ReloadablePropertySource interface {
+	core.PropertySource
+	Reload() error
+}
+
+type FilePropertySource struct {
+	name        string
+	filePaths   []string
+	propertyMap map[string]interface{}
+	mu          *sync.RWMutex
+}
+
+func NewFilePropertySource(name string, filePaths ...string) (*FilePropertySource, error) {
+	if name == "" {
+		return nil, errors.New("name must not be null")
+	} else if len(filePaths) == 0 {
+		return nil, errors.New("file paths must not be empty")
+	}
+	propertySource := &FilePropertySource{
+		name:        name,
+		filePaths:   filePaths,
+		propertyMap: make(map[string]interface{}, 0),
+		mu:          &sync.RWMutex{},
+	}
+	if err := propertySource.Reload(); err != nil {
+		return nil, err
+	}
+	return propertySource, nil
+}
+
+func (propertySource *FilePropertySource) GetName() string {
+	return propertySource.name
+}
+
+func (propertySource *FilePropertySource) GetSource() interface{} {
+	propertySource.mu.RLock()
+	defer propertySource.mu.RUnlock()
+	return propertySource.propertyMap
+}
+
+func (propertySource *FilePropertySource) GetProperty(name string) interface{} {
+	propertySource.mu.RLock()
+	defer propertySource.mu.RUnlock()
+	return propertySource.propertyMap[name]
+}
+
+func (propertySource *FilePropertySource) ContainsProperty(name string) bool {
+	propertySource.mu.RLock()
+	defer propertySource.mu.RUnlock()
+	_, ok := propertySource.propertyMap[name]
+	return ok
+}
+
+func (propertySource *FilePropertySource) GetPropertyNames() []string {
+	propertySource.mu.RLock()
+	defer propertySource.mu.RUnlock()
+	propertyNames := make([]string, 0, len(propertySource.propertyMap))
+	for propertyName := range propertySource.propertyMap {
+		propertyNames = append(propertyNames, propertyName)
+	}
+	return propertyNames
+}
+
+func (propertySource *FilePropertySource) Reload() error {
+	/* the files are parsed in the same way as the application files, the current values are kept if they are invalid */
+	propertyMap, err := core.NewAppFileParser().Parse(propertySource.filePaths)
+	if err != nil {
+		return err
+	}
+	propertySource.mu.Lock()
+	propertySource.propertyMap = propertyMap
+	propertySource.mu.Unlock()
+	return nil
+}
+
+type PropertiesHolder struct {
+	value atomic.Value
+}
+
+func NewPropertiesHolder(properties ConfigurationProperties) *PropertiesHolder {
+	if properties == nil {
+		panic("Properties must not be null")
+	}
+	holder := &PropertiesHolder{}
+	holder.value.Store(properties)
+	return holder
+}
+
+func (holder *PropertiesHolder) Get() ConfigurationProperties {
+	return holder.value.Load().(ConfigurationProperties)
+}
+
+func (holder *PropertiesHolder) set(properties ConfigurationProperties) {
+	holder.value.Store(properties)
+}
+
+type PropertiesRefreshError struct {
+	peaName string
+	cause   error
+}
+
+func NewPropertiesRefreshError(peaName string, cause error) PropertiesRefreshError {
+	return PropertiesRefreshError{
+		peaName,
+		cause,
+	}
+}
+
+func (err PropertiesRefreshError) GetPeaName() string {
+	return err.peaName
+}
+
+func (err PropertiesRefreshError) GetCause() error {
+	return err.cause
+}
+
+func (err PropertiesRefreshError) Unwrap() error {
+	return err.cause
+}
+
+func (err PropertiesRefreshError) Error() string {
+	return "properties of the pea '" + err.peaName + "' could not be refreshed : " + err.cause.Error()
+}
+
+type PropertiesRefreshErrors struct {
+	errors []PropertiesRefreshError
+}
+
+func NewPropertiesRefreshErrors(errors []PropertiesRefreshError) PropertiesRefreshErrors {
+	return PropertiesRefreshErrors{
+		errors,
+	}
+}
+
+func (err PropertiesRefreshErrors) GetErrors() []PropertiesRefreshError {
+	return err.errors
+}
+
+func (err PropertiesRefreshErrors) Unwrap() error {
+	if len(err.errors) == 0 {
+		return nil
+	}
+	return err.errors[0]
+}
+
+func (err PropertiesRefreshErrors) Error() string {
+	messages := make([]string, 0)
+	for _, refreshError := range err.errors {
+		messages = append(messages, refreshError.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (ctx *BaseApplicationContext) GetPropertiesHolder(peaName string) (*PropertiesHolder, error) {
+	ctx.muRefresh.Lock()
+	holder, ok := ctx.propertiesHolders[peaName]
+	ctx.muRefresh.Unlock()
+	if ok {
+		return holder, nil
+	}
+	pea, err := ctx.GetPea(peaName)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := pea.(ConfigurationProperties); !ok || reflect.TypeOf(pea).Kind() != reflect.Ptr {
+		return nil, errors.New("pea '" + peaName + "' is not a pointer to configuration properties")
+	}
+	ctx.muRefresh.Lock()
+	defer ctx.muRefresh.Unlock()
+	if holder, ok := ctx.propertiesHolders[peaName]; ok {
+		return holder, nil
+	}
+	/* the holder is bound from the current environment, the pea itself might have been created before a refresh */
+	properties, err := ctx.bindProperties(reflect.TypeOf(pea).Elem())
+	if err != nil {
+		return nil, err
+	}
+	holder = NewPropertiesHolder(properties)
+	ctx.propertiesHolders[peaName] = holder
+	return holder, nil
+}
+
+func (ctx *BaseApplicationContext) Refresh() ([]string, error) {
+	changedKeys, refreshErrors, err := ctx.refreshEnvironment()
+	if err != nil || len(changedKeys) == 0 {
+		return changedKeys, err
+	}
+	/* the event is published without holding the lock, the listeners might use the context */
+	if ctx.applicationEventBroadcaster != nil {
+		ctx.PublishEvent(NewEnvironmentChangedEvent(ctx, changedKeys))
+	}
+	if len(refreshErrors) != 0 {
+		return changedKeys, NewPropertiesRefreshErrors(refreshErrors)
+	}
+	return changedKeys, nil
+}
+
+func (ctx *BaseApplicationContext) refreshEnvironment() ([]string, []PropertiesRefreshError, error) {
+	ctx.muRefresh.Lock()
+	defer ctx.muRefresh.Unlock()
+	if ctx.environment == nil {
+		return nil, nil, errors.New("environment must not be null")
+	} else if ctx.environment.GetPropertySources() == nil {
+		return nil, nil, errors.New("property sources must not be null")
+	}
+	for _, propertySource := range ctx.environment.GetPropertySources().GetPropertyResources() {
+		if reloadablePropertySource, ok := propertySource.(ReloadablePropertySource); ok {
+			if err := reloadablePropertySource.Reload(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	snapshot := ctx.takePropertySnapshot()
+	changedKeys := make([]string, 0)
+	for key, value := range snapshot {
+		if previousValue, ok := ctx.propertySnapshot[key]; !ok || previousValue != value {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	for key := range ctx.propertySnapshot {
+		if _, ok := snapshot[key]; !ok {
+			changedKeys = append(changedKeys, key)
+		}
+	}
+	ctx.propertySnapshot = snapshot
+	if len(changedKeys) == 0 {
+		return changedKeys, nil, nil
+	}
+	sort.Strings(changedKeys)
+
+	refreshErrors := make([]PropertiesRefreshError, 0)
+	for _, peaName := range ctx.getRefreshablePeaNames() {
+		if err := ctx.refreshProperties(peaName, changedKeys, snapshot); err != nil {
+			refreshErrors = append(refreshErrors, NewPropertiesRefreshError(peaName, err))
+		}
+	}
+	return changedKeys, refreshErrors, nil
+}
+
+func (ctx *BaseApplicationContext) WatchEnvironment(interval time.Duration) func() {
+	if interval <= 0 {
+		panic("Interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+	once := &sync.Once{}
+	stop := func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+	/* the watchers are stopped when the context is closed */
+	ctx.muRefresh.Lock()
+	ctx.environmentWatchers = append(ctx.environmentWatchers, stop)
+	ctx.muRefresh.Unlock()
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := ctx.Refresh(); err != nil && ctx.logger != nil {
+					ctx.logger.Error(ctx, "Environment could not be refreshed : "+err.Error())
+				}
+			}
+		}
+	}()
+	return stop
+}
+
+func (ctx *BaseApplicationContext) stopWatchingEnvironment() {
+	ctx.muRefresh.Lock()
+	environmentWatchers := ctx.environmentWatchers
+	ctx.environmentWatchers = make([]func(), 0)
+	ctx.muRefresh.Unlock()
+	for _, stop := range environmentWatchers {
+		stop()
+	}
+}
+
+func (ctx *BaseApplicationContext) getRefreshablePeaNames() []string {
+	peaNames := make([]string, 0)
+	for peaName := range ctx.propertiesHolders {
+		peaNames = append(peaNames, peaName)
+	}
+	sort.Strings(peaNames)
+	return peaNames
+}
+
+func (ctx *BaseApplicationContext) refreshProperties(peaName string, changedKeys []string, snapshot map[string]string) error {
+	holder := ctx.propertiesHolders[peaName]
+	properties := holder.Get()
+	if !ctx.isAffected(properties, changedKeys, snapshot) {
+		return nil
+	}
+	/* the properties are bound into a new instance so that a failure does not leave them half-updated */
+	newProperties, err := ctx.bindProperties(reflect.TypeOf(properties).Elem())
+	if err != nil {
+		return err
+	}
+	holder.set(newProperties)
+	if ctx.logger != nil {
+		ctx.logger.Debug(ctx, "Properties of the pea '"+peaName+"' have been refreshed")
+	}
+	return nil
+}
+
+func (ctx *BaseApplicationContext) bindProperties(typ reflect.Type) (ConfigurationProperties, error) {
+	if ctx.environment == nil {
+		return nil, errors.New("environment must not be null")
+	}
+	newInstance := reflect.New(typ)
+	binder := newConfigurationPropertiesBinder(ctx.environment, ctx.environment.GetTypeConverterService())
+	if err := binder.Bind(newInstance.Interface()); err != nil {
+		return nil, err
+	}
+	return newInstance.Interface().(ConfigurationProperties), nil
+}
+
+func (ctx *BaseApplicationContext) isAffected(properties ConfigurationProperties, changedKeys []string, snapshot map[string]string) bool {
+	prefix := getUniformPropertyName(GetCanonicalPropertyName(properties.GetConfigurationPrefix()))
+	isUnderPrefix := func(key string) bool {
+		uniformKey := getUniformPropertyName(key)
+		return uniformKey == prefix || strings.HasPrefix(uniformKey, prefix+".") || strings.HasPrefix(uniformKey, prefix+"[")
+	}
+	for _, key := range changedKeys {
+		if isUnderPrefix(key) {
+			return true
+		}
+	}
+	/* the values having placeholders might refer to any of the changed properties */
+	for key, value := range snapshot {
+		if isUnderPrefix(key) && strings.Contains(value, placeholderPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *BaseApplicationContext) takePropertySnapshot() map[string]string {
+	snapshot := make(map[string]string, 0)
+	if ctx.environment == nil || ctx.environment.GetPropertySources() == nil {
+		return snapshot
+	}
+	for _, propertySource := range ctx.environment.GetPropertySources().GetPropertyResources() {
+		for _, name := range propertySource.GetPropertyNames() {
+			if _, ok := snapshot[name]; !ok {
+				snapshot[name] = fmt.Sprint(propertySource.GetProperty(name))
+			}
+		}
+	}
+	return snapshot
+}
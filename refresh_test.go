@@ -0,0 +1,373 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testReloadablePropertySource struct {
+	properties map[string]interface{}
+	next       map[string]interface{}
+	err        error
+}
+
+func (source *testReloadablePropertySource) GetName() string {
+	return "testReloadablePropertySource"
+}
+
+func (source *testReloadablePropertySource) GetSource() interface{} {
+	return source.properties
+}
+
+func (source *testReloadablePropertySource) GetProperty(name string) interface{} {
+	return source.properties[name]
+}
+
+func (source *testReloadablePropertySource) ContainsProperty(name string) bool {
+	_, ok := source.properties[name]
+	return ok
+}
+
+func (source *testReloadablePropertySource) GetPropertyNames() []string {
+	names := make([]string, 0)
+	for name := range source.properties {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (source *testReloadablePropertySource) Reload() error {
+	if source.err != nil {
+		return source.err
+	}
+	if source.next != nil {
+		source.properties = source.next
+		source.next = nil
+	}
+	return nil
+}
+
+type testRefreshableProperties struct {
+	Host    string `json:"host" default:"localhost"`
+	Port    int    `json:"port" min:"1"`
+	Address string `json:"address" default:"${refresh.host:localhost}:${refresh.port}"`
+}
+
+func newTestRefreshableProperties() *testRefreshableProperties {
+	return &testRefreshableProperties{}
+}
+
+func (properties *testRefreshableProperties) GetConfigurationPrefix() string {
+	return "refresh"
+}
+
+type testOtherProperties struct {
+	Name string `json:"name"`
+}
+
+func newTestOtherProperties() *testOtherProperties {
+	return &testOtherProperties{}
+}
+
+func (properties *testOtherProperties) GetConfigurationPrefix() string {
+	return "other"
+}
+
+func TestBaseApplicationContext_Refresh(t *testing.T) {
+	propertySource := &testReloadablePropertySource{
+		properties: map[string]interface{}{"refresh.host": "first", "refresh.port": "80", "other.name": "test"},
+	}
+	environment := core.NewStandardEnvironment()
+	environment.GetPropertySources().Add(propertySource)
+
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(environment)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("refreshableProperties", NewScannedPeaDefinition("refreshableProperties", goo.GetType(newTestRefreshableProperties)))
+	registry.RegisterPeaDefinition("otherProperties", NewScannedPeaDefinition("otherProperties", goo.GetType(newTestOtherProperties)))
+
+	listener := &testEnvironmentChangedListener{}
+	baseApplicationContext.AddApplicationListener(listener)
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	pea, err := baseApplicationContext.GetPea("refreshableProperties")
+	assert.Nil(t, err)
+	properties := pea.(*testRefreshableProperties)
+	assert.Equal(t, "first:80", properties.Address)
+	otherPea, _ := baseApplicationContext.GetPea("otherProperties")
+	otherProperties := otherPea.(*testOtherProperties)
+
+	holder, err := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	assert.Nil(t, err)
+	oldSnapshot := holder.Get().(*testRefreshableProperties)
+	assert.Equal(t, "first", oldSnapshot.Host)
+
+	_, err = baseApplicationContext.GetPropertiesHolder("environment")
+	assert.NotNil(t, err)
+
+	keys, err := baseApplicationContext.Refresh()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(keys))
+
+	propertySource.next = map[string]interface{}{"refresh.port": "8080", "other.name": "test", "refresh.timeout": "5"}
+	otherProperties.Name = "modified"
+	keys, err = baseApplicationContext.Refresh()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refresh.host", "refresh.port", "refresh.timeout"}, keys)
+	/* the injected pea is never modified, the refreshed values are only visible through the holder */
+	assert.Equal(t, "first", properties.Host)
+	assert.Equal(t, 80, properties.Port)
+	assert.Equal(t, "modified", otherProperties.Name)
+	assert.Equal(t, "first", oldSnapshot.Host)
+	newSnapshot := holder.Get().(*testRefreshableProperties)
+	assert.Equal(t, "localhost", newSnapshot.Host)
+	assert.Equal(t, 8080, newSnapshot.Port)
+	assert.Equal(t, "localhost:8080", newSnapshot.Address)
+	assert.Equal(t, 1, len(listener.events))
+	assert.Equal(t, keys, listener.events[0].GetKeys())
+
+	propertySource.next = map[string]interface{}{"refresh.port": "0", "other.name": "test"}
+	keys, err = baseApplicationContext.Refresh()
+	assert.NotNil(t, err)
+	refreshErrors, ok := err.(PropertiesRefreshErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(refreshErrors.GetErrors()))
+	var refreshError PropertiesRefreshError
+	assert.True(t, errors.As(err, &refreshError))
+	assert.Equal(t, "refreshableProperties", refreshError.GetPeaName())
+	var validationError ValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, 8080, holder.Get().(*testRefreshableProperties).Port)
+
+	otherHolder, err := baseApplicationContext.GetPropertiesHolder("otherProperties")
+	assert.Nil(t, err)
+	assert.Equal(t, "test", otherHolder.Get().(*testOtherProperties).Name)
+
+	propertySource.err = errors.New("test-reload-error")
+	_, err = baseApplicationContext.Refresh()
+	assert.Equal(t, "test-reload-error", err.Error())
+}
+
+func TestBaseApplicationContext_WatchEnvironment(t *testing.T) {
+	propertySource := &testReloadablePropertySource{
+		properties: map[string]interface{}{"refresh.host": "first", "refresh.port": "80"},
+	}
+	environment := core.NewStandardEnvironment()
+	environment.GetPropertySources().Add(propertySource)
+
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(environment)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("refreshableProperties", NewScannedPeaDefinition("refreshableProperties", goo.GetType(newTestRefreshableProperties)))
+	assert.Nil(t, baseApplicationContext.Configure())
+	holder, err := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	assert.Nil(t, err)
+
+	assert.Panics(t, func() {
+		baseApplicationContext.WatchEnvironment(0)
+	})
+	propertySource.next = map[string]interface{}{"refresh.host": "second", "refresh.port": "80"}
+	stop := baseApplicationContext.WatchEnvironment(5 * time.Millisecond)
+	defer stop()
+	assert.Eventually(t, func() bool {
+		return holder.Get().(*testRefreshableProperties).Host == "second"
+	}, time.Second, 5*time.Millisecond)
+}
+
+type testEnvironmentChangedListener struct {
+	events []EnvironmentChangedEvent
+}
+
+func (listener *testEnvironmentChangedListener) GetApplicationListenerName() string {
+	return "testEnvironmentChangedListener"
+}
+
+func (listener *testEnvironmentChangedListener) SubscribeEvents() []ApplicationEventId {
+	return []ApplicationEventId{EnvironmentChangedEventId()}
+}
+
+func (listener *testEnvironmentChangedListener) OnApplicationEvent(context Context, event ApplicationEvent) {
+	listener.events = append(listener.events, event.(EnvironmentChangedEvent))
+}
+
+type testRefreshingListener struct {
+	holders []*PropertiesHolder
+}
+
+func (listener *testRefreshingListener) GetApplicationListenerName() string {
+	return "testRefreshingListener"
+}
+
+func (listener *testRefreshingListener) SubscribeEvents() []ApplicationEventId {
+	return []ApplicationEventId{EnvironmentChangedEventId()}
+}
+
+func (listener *testRefreshingListener) OnApplicationEvent(context Context, event ApplicationEvent) {
+	baseApplicationContext := context.(*BaseApplicationContext)
+	holder, _ := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	listener.holders = append(listener.holders, holder)
+	baseApplicationContext.Refresh()
+}
+
+func newTestRefreshContext(propertySource core.PropertySource) *BaseApplicationContext {
+	environment := core.NewStandardEnvironment()
+	environment.GetPropertySources().Add(propertySource)
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(environment)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("refreshableProperties", NewScannedPeaDefinition("refreshableProperties", goo.GetType(newTestRefreshableProperties)))
+	return baseApplicationContext
+}
+
+func TestBaseApplicationContext_RefreshWithListenerUsingTheContext(t *testing.T) {
+	propertySource := &testReloadablePropertySource{
+		properties: map[string]interface{}{"refresh.host": "first", "refresh.port": "80"},
+	}
+	baseApplicationContext := newTestRefreshContext(propertySource)
+	listener := &testRefreshingListener{}
+	baseApplicationContext.AddApplicationListener(listener)
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	propertySource.next = map[string]interface{}{"refresh.host": "second", "refresh.port": "80"}
+	done := make(chan error)
+	go func() {
+		_, err := baseApplicationContext.Refresh()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "listener could not use the context while it was being refreshed")
+	}
+	/* a holder created after a refresh has the current values */
+	assert.Equal(t, 1, len(listener.holders))
+	assert.Equal(t, "second", listener.holders[0].Get().(*testRefreshableProperties).Host)
+}
+
+func TestBaseApplicationContext_RefreshWithConcurrentReaders(t *testing.T) {
+	propertySource := &testReloadablePropertySource{
+		properties: map[string]interface{}{"refresh.host": "host-0", "refresh.port": "80"},
+	}
+	baseApplicationContext := newTestRefreshContext(propertySource)
+	assert.Nil(t, baseApplicationContext.Configure())
+	pea, err := baseApplicationContext.GetPea("refreshableProperties")
+	assert.Nil(t, err)
+	holder, err := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	assert.Nil(t, err)
+
+	done := make(chan bool)
+	readers := &sync.WaitGroup{}
+	for reader := 0; reader < 4; reader++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					properties := holder.Get().(*testRefreshableProperties)
+					/* a snapshot is never half-updated, its address always matches its host */
+					if properties.Address != properties.Host+":80" {
+						t.Errorf("inconsistent snapshot : %s, %s", properties.Host, properties.Address)
+					}
+					_ = pea.(*testRefreshableProperties).Host
+				}
+			}
+		}()
+	}
+	for index := 1; index <= 20; index++ {
+		propertySource.next = map[string]interface{}{"refresh.host": "host-" + strconv.Itoa(index), "refresh.port": "80"}
+		_, err = baseApplicationContext.Refresh()
+		assert.Nil(t, err)
+	}
+	close(done)
+	readers.Wait()
+	assert.Equal(t, "host-20", holder.Get().(*testRefreshableProperties).Host)
+	assert.Equal(t, "host-0", pea.(*testRefreshableProperties).Host)
+}
+
+func TestBaseApplicationContext_RefreshFilePropertySource(t *testing.T) {
+	_, err := NewFilePropertySource("", "test.yaml")
+	assert.NotNil(t, err)
+	_, err = NewFilePropertySource("file")
+	assert.NotNil(t, err)
+	_, err = NewFilePropertySource("file", "missing.yaml")
+	assert.NotNil(t, err)
+
+	directory, err := ioutil.TempDir("", "refresh")
+	assert.Nil(t, err)
+	defer os.RemoveAll(directory)
+	filePath := filepath.Join(directory, "application.yaml")
+	writeFile := func(host string) {
+		assert.Nil(t, ioutil.WriteFile(filePath, []byte("refresh:\n  host: "+host+"\n  port: 80\n"), 0644))
+	}
+	writeFile("first")
+
+	propertySource, err := NewFilePropertySource("file", filePath)
+	assert.Nil(t, err)
+	assert.Equal(t, "file", propertySource.GetName())
+	assert.Equal(t, "first", propertySource.GetProperty("refresh.host"))
+	baseApplicationContext := newTestRefreshContext(propertySource)
+	assert.Nil(t, baseApplicationContext.Configure())
+	holder, err := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	assert.Nil(t, err)
+
+	writeFile("second")
+	keys, err := baseApplicationContext.Refresh()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"refresh.host"}, keys)
+	assert.Equal(t, "second", holder.Get().(*testRefreshableProperties).Host)
+
+	/* the current values are kept if the file is invalid */
+	assert.Nil(t, ioutil.WriteFile(filePath, []byte("refresh: ["), 0644))
+	_, err = baseApplicationContext.Refresh()
+	assert.NotNil(t, err)
+	assert.Equal(t, "second", propertySource.GetProperty("refresh.host"))
+	assert.Equal(t, "second", holder.Get().(*testRefreshableProperties).Host)
+}
+
+func TestBaseApplicationContext_CloseStopsWatchingEnvironment(t *testing.T) {
+	directory, err := ioutil.TempDir("", "refresh")
+	assert.Nil(t, err)
+	defer os.RemoveAll(directory)
+	filePath := filepath.Join(directory, "application.yaml")
+	writeFile := func(host string) {
+		assert.Nil(t, ioutil.WriteFile(filePath, []byte("refresh:\n  host: "+host+"\n  port: 80\n"), 0644))
+	}
+	writeFile("first")
+
+	propertySource, err := NewFilePropertySource("file", filePath)
+	assert.Nil(t, err)
+	baseApplicationContext := newTestRefreshContext(propertySource)
+	assert.Nil(t, baseApplicationContext.Configure())
+	holder, err := baseApplicationContext.GetPropertiesHolder("refreshableProperties")
+	assert.Nil(t, err)
+
+	stop := baseApplicationContext.WatchEnvironment(5 * time.Millisecond)
+	writeFile("second")
+	assert.Eventually(t, func() bool {
+		return holder.Get().(*testRefreshableProperties).Host == "second"
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Nil(t, baseApplicationContext.Close())
+	time.Sleep(20 * time.Millisecond)
+	writeFile("third")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "second", holder.Get().(*testRefreshableProperties).Host)
+	assert.NotPanics(t, stop)
+}
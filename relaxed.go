@@ -0,0 +1,170 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type AmbiguousPropertyError struct {
+	propertyName string
+	sourceName   string
+	candidates   []string
+}
+
+func NewAmbiguousPropertyError(propertyName string, sourceName string, candidates []string) AmbiguousPropertyError {
+	return AmbiguousPropertyError{
+		propertyName,
+		sourceName,
+		candidates,
+	}
+}
+
+func (err AmbiguousPropertyError) GetPropertyName() string {
+	return err.propertyName
+}
+
+func (err AmbiguousPropertyError) GetSourceName() string {
+	return err.sourceName
+}
+
+func (err AmbiguousPropertyError) GetCandidates() []string {
+	return err.candidates
+}
+
+func (err AmbiguousPropertyError) Error() string {
+	return "property '" + err.propertyName + "' is ambiguous, more than one property matches it in the source '" +
+		err.sourceName + "' : " + strings.Join(err.candidates, ", ")
+}
+
+/*
+The canonical form of a property name consists of lower-case segments separated by dots,
+and words are separated by hyphens, e.g. server.max-connections. The names server.maxConnections,
+server.max_connections and the environment variable SERVER_MAXCONNECTIONS match it.
+*/
+func GetCanonicalPropertyName(name string) string {
+	segments := strings.Split(getDottedPropertyName(name), ".")
+	for index, segment := range segments {
+		segments[index] = getCanonicalSegment(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+func getCanonicalSegment(segment string) string {
+	var builder strings.Builder
+	runes := []rune(segment)
+	for index, char := range runes {
+		if char == '_' {
+			char = '-'
+		}
+		if unicode.IsUpper(char) && index > 0 && (unicode.IsLower(runes[index-1]) || unicode.IsDigit(runes[index-1])) {
+			builder.WriteRune('-')
+		}
+		builder.WriteRune(unicode.ToLower(char))
+	}
+	return builder.String()
+}
+
+func isEnvironmentVariableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, char := range name {
+		if !(unicode.IsUpper(char) || unicode.IsDigit(char) || char == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func getDottedPropertyName(name string) string {
+	/* the underscores of environment variables separate the segments */
+	if isEnvironmentVariableName(name) {
+		return strings.ToLower(strings.ReplaceAll(name, "_", "."))
+	}
+	return name
+}
+
+func getUniformPropertyName(name string) string {
+	segments := strings.Split(getDottedPropertyName(name), ".")
+	for index, segment := range segments {
+		segment = strings.ReplaceAll(segment, "-", "")
+		segment = strings.ReplaceAll(segment, "_", "")
+		segments[index] = strings.ToLower(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+type relaxedProperty struct {
+	name        string
+	dottedName  string
+	uniformName string
+	source      core.PropertySource
+}
+
+type relaxedPropertyIndex struct {
+	properties []relaxedProperty
+	sources    []core.PropertySource
+	uniform    map[string][]relaxedProperty
+}
+
+func newRelaxedPropertyIndex(env core.Environment) relaxedPropertyIndex {
+	index := relaxedPropertyIndex{
+		properties: make([]relaxedProperty, 0),
+		sources:    make([]core.PropertySource, 0),
+		uniform:    make(map[string][]relaxedProperty, 0),
+	}
+	configurableEnv, ok := env.(core.ConfigurableEnvironment)
+	if !ok || configurableEnv.GetPropertySources() == nil {
+		return index
+	}
+	for _, propertySource := range configurableEnv.GetPropertySources().GetPropertyResources() {
+		if propertySource == nil {
+			continue
+		}
+		index.sources = append(index.sources, propertySource)
+		names := propertySource.GetPropertyNames()
+		sort.Strings(names)
+		for _, name := range names {
+			property := relaxedProperty{
+				name,
+				getDottedPropertyName(name),
+				getUniformPropertyName(name),
+				propertySource,
+			}
+			index.properties = append(index.properties, property)
+			index.uniform[property.uniformName] = append(index.uniform[property.uniformName], property)
+		}
+	}
+	return index
+}
+
+func (index relaxedPropertyIndex) find(name string) ([]relaxedProperty, core.PropertySource) {
+	candidates := index.uniform[getUniformPropertyName(name)]
+	/* the first source containing the property takes precedence, all of its spellings are returned to detect ambiguities */
+	for _, propertySource := range index.sources {
+		matches := make([]relaxedProperty, 0)
+		for _, candidate := range candidates {
+			if candidate.source.GetName() != propertySource.GetName() {
+				continue
+			}
+			matches = append(matches, candidate)
+		}
+		if len(matches) != 0 {
+			return matches, propertySource
+		}
+	}
+	return nil, nil
+}
+
+func (index relaxedPropertyIndex) getPropertiesWithPrefix(prefix string) []relaxedProperty {
+	properties := make([]relaxedProperty, 0)
+	uniformPrefix := getUniformPropertyName(prefix)
+	for _, property := range index.properties {
+		if strings.HasPrefix(property.uniformName, uniformPrefix+".") || strings.HasPrefix(property.uniformName, uniformPrefix+"[") {
+			properties = append(properties, property)
+		}
+	}
+	return properties
+}
@@ -0,0 +1,106 @@
+package context
+
+import (
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestGetCanonicalPropertyName(t *testing.T) {
+	assert.Equal(t, "server.max-connections", GetCanonicalPropertyName("server.maxConnections"))
+	assert.Equal(t, "server.max-connections", GetCanonicalPropertyName("server.max-connections"))
+	assert.Equal(t, "server.max-connections", GetCanonicalPropertyName("server.max_connections"))
+	assert.Equal(t, "server.maxconnections", GetCanonicalPropertyName("SERVER_MAXCONNECTIONS"))
+	assert.Equal(t, "server.hosts[0].port1", GetCanonicalPropertyName("server.hosts[0].port1"))
+}
+
+func TestGetUniformPropertyName(t *testing.T) {
+	assert.Equal(t, "server.maxconnections", getUniformPropertyName("server.maxConnections"))
+	assert.Equal(t, "server.maxconnections", getUniformPropertyName("server.max-connections"))
+	assert.Equal(t, "server.maxconnections", getUniformPropertyName("server.max_connections"))
+	assert.Equal(t, "server.maxconnections", getUniformPropertyName("SERVER_MAXCONNECTIONS"))
+	assert.Equal(t, "servers.0.host", getUniformPropertyName("SERVERS_0_HOST"))
+}
+
+type testRelaxedProperties struct {
+	MaxConnections int               `json:"maxConnections"`
+	IdleTimeout    int               `json:"idle-timeout"`
+	ReadTimeout    int               `json:"read_timeout"`
+	Labels         map[string]string `json:"labels"`
+	Hosts          []string          `json:"hosts"`
+}
+
+func (testRelaxedProperties) GetConfigurationPrefix() string {
+	return "relaxedServer"
+}
+
+func TestConfigurationPropertiesBinder_BindRelaxedNames(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--relaxed-server.max_connections=10", "--relaxedServer.idleTimeout=20", "--relaxed_server.labels.Env=prod"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testRelaxedProperties{}
+	assert.Nil(t, propertiesBindingProcessor.Bind(properties))
+	assert.Equal(t, 10, properties.MaxConnections)
+	assert.Equal(t, 20, properties.IdleTimeout)
+	assert.Equal(t, map[string]string{"Env": "prod"}, properties.Labels)
+}
+
+func TestConfigurationPropertiesBinder_BindEnvironmentVariables(t *testing.T) {
+	os.Setenv("RELAXEDSERVER_MAXCONNECTIONS", "30")
+	os.Setenv("RELAXEDSERVER_READTIMEOUT", "40")
+	os.Setenv("RELAXEDSERVER_HOSTS_1", "second")
+	os.Setenv("RELAXEDSERVER_HOSTS_0", "first")
+	defer func() {
+		os.Unsetenv("RELAXEDSERVER_MAXCONNECTIONS")
+		os.Unsetenv("RELAXEDSERVER_READTIMEOUT")
+		os.Unsetenv("RELAXEDSERVER_HOSTS_1")
+		os.Unsetenv("RELAXEDSERVER_HOSTS_0")
+	}()
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app", "--relaxedServer.read-timeout=50"}))
+	standardEnvironment.GetPropertySources().Add(core.NewSystemEnvironmentPropertySource())
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testRelaxedProperties{}
+	assert.Nil(t, propertiesBindingProcessor.Bind(properties))
+	assert.Equal(t, 30, properties.MaxConnections)
+	assert.Equal(t, 50, properties.ReadTimeout)
+	assert.Equal(t, []string{"first", "second"}, properties.Hosts)
+}
+
+func TestConfigurationPropertiesBinder_BindAmbiguousNames(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--relaxedServer.maxConnections=10", "--relaxedServer.max_connections=20"}))
+	propertiesBindingProcessor := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties := &testRelaxedProperties{}
+	err := propertiesBindingProcessor.Bind(properties)
+	assert.NotNil(t, err)
+	bindError, ok := err.(BindError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(bindError.GetFailures()))
+	assert.Equal(t, "relaxed-server.max-connections", bindError.GetFailures()[0].GetPropertyName())
+	ambiguousPropertyError, ok := bindError.GetFailures()[0].GetCause().(AmbiguousPropertyError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"relaxedServer.maxConnections", "relaxedServer.max_connections"}, ambiguousPropertyError.GetCandidates())
+	assert.Equal(t, 0, properties.MaxConnections)
+
+	/* the canonical name is ambiguous as well if another spelling is given in the same source */
+	standardEnvironment = core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--relaxed-server.max-connections=10", "--relaxedServer.maxConnections=20"}))
+	propertiesBindingProcessor = newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	properties = &testRelaxedProperties{}
+	err = propertiesBindingProcessor.Bind(properties)
+	bindError, ok = err.(BindError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(bindError.GetFailures()))
+	ambiguousPropertyError, ok = bindError.GetFailures()[0].GetCause().(AmbiguousPropertyError)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(ambiguousPropertyError.GetCandidates()))
+}
@@ -0,0 +1,80 @@
+package context
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+const applicationArguments = "applicationArguments"
+
+type ApplicationArguments struct {
+	sourceArgs    []string
+	optionArgs    map[string][]string
+	nonOptionArgs []string
+}
+
+func NewApplicationArguments(args []string) (ApplicationArguments, error) {
+	arguments := ApplicationArguments{
+		sourceArgs:    args,
+		optionArgs:    make(map[string][]string, 0),
+		nonOptionArgs: make([]string, 0),
+	}
+	for index, arg := range args {
+		/* the arguments after the end of the options are not options, even if they start with dashes */
+		if arg == "--" {
+			arguments.nonOptionArgs = append(arguments.nonOptionArgs, args[index+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			arguments.nonOptionArgs = append(arguments.nonOptionArgs, arg)
+			continue
+		}
+		optionName := arg[2:]
+		optionValue := ""
+		if index := strings.Index(optionName, "="); index > -1 {
+			optionValue = strings.TrimSpace(optionName[index+1:])
+			optionName = optionName[:index]
+		}
+		optionName = strings.TrimSpace(optionName)
+		if optionName == "" {
+			return arguments, errors.New("invalid argument syntax : " + arg)
+		}
+		arguments.optionArgs[optionName] = append(arguments.optionArgs[optionName], optionValue)
+	}
+	return arguments, nil
+}
+
+func (arguments ApplicationArguments) GetSourceArgs() []string {
+	return arguments.sourceArgs
+}
+
+func (arguments ApplicationArguments) GetOptionNames() []string {
+	optionNames := make([]string, 0)
+	for optionName := range arguments.optionArgs {
+		optionNames = append(optionNames, optionName)
+	}
+	sort.Strings(optionNames)
+	return optionNames
+}
+
+func (arguments ApplicationArguments) ContainsOption(name string) bool {
+	_, ok := arguments.optionArgs[name]
+	return ok
+}
+
+func (arguments ApplicationArguments) GetOptionValues(name string) []string {
+	return arguments.optionArgs[name]
+}
+
+func (arguments ApplicationArguments) GetNonOptionArgs() []string {
+	return arguments.nonOptionArgs
+}
+
+type ApplicationRunner interface {
+	Run(args ApplicationArguments) error
+}
+
+type CommandLineRunner interface {
+	Run(args ...string) error
+}
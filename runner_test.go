@@ -0,0 +1,187 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNewApplicationArguments(t *testing.T) {
+	arguments, err := NewApplicationArguments([]string{"migrate", "--profile=dev", "--profile=test", "--verbose", "users"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"profile", "verbose"}, arguments.GetOptionNames())
+	assert.True(t, arguments.ContainsOption("verbose"))
+	assert.False(t, arguments.ContainsOption("missing"))
+	assert.Equal(t, []string{"dev", "test"}, arguments.GetOptionValues("profile"))
+	assert.Equal(t, []string{""}, arguments.GetOptionValues("verbose"))
+	assert.Equal(t, []string{"migrate", "users"}, arguments.GetNonOptionArgs())
+	assert.Equal(t, 5, len(arguments.GetSourceArgs()))
+
+	_, err = NewApplicationArguments([]string{"--=value"})
+	assert.NotNil(t, err)
+
+	arguments, err = NewApplicationArguments([]string{"--verbose", "--", "file.txt", "--not-an-option"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"verbose"}, arguments.GetOptionNames())
+	assert.Equal(t, []string{"file.txt", "--not-an-option"}, arguments.GetNonOptionArgs())
+}
+
+func TestBaseApplicationContext_ConfigureWithEndOfOptions(t *testing.T) {
+	args := os.Args
+	defer func() {
+		os.Args = args
+	}()
+	os.Args = []string{"app", "--", "file.txt"}
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	assert.Nil(t, baseApplicationContext.Configure())
+
+	arguments, err := baseApplicationContext.GetApplicationArguments()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"file.txt"}, arguments.GetNonOptionArgs())
+}
+
+var testInvokedRunners = make([]string, 0)
+
+type testApplicationRunner struct {
+}
+
+func newTestApplicationRunner() testApplicationRunner {
+	return testApplicationRunner{}
+}
+
+func (runner testApplicationRunner) GetOrder() int {
+	return 2
+}
+
+func (runner testApplicationRunner) Run(args ApplicationArguments) error {
+	testInvokedRunners = append(testInvokedRunners, "application:"+args.GetNonOptionArgs()[0])
+	return nil
+}
+
+type testCommandLineRunner struct {
+}
+
+func newTestCommandLineRunner() testCommandLineRunner {
+	return testCommandLineRunner{}
+}
+
+func (runner testCommandLineRunner) GetOrder() int {
+	return 1
+}
+
+func (runner testCommandLineRunner) Run(args ...string) error {
+	testInvokedRunners = append(testInvokedRunners, "command-line:"+args[0])
+	return nil
+}
+
+type testFailingRunner struct {
+}
+
+func newTestFailingRunner() testFailingRunner {
+	return testFailingRunner{}
+}
+
+func (runner testFailingRunner) Run(args ApplicationArguments) error {
+	return errors.New("test-runner-failure")
+}
+
+type testFailureListener struct {
+	events *[]ApplicationEvent
+}
+
+func (listener testFailureListener) GetApplicationListenerName() string {
+	return "github.com.procyon.projects.testFailureListener"
+}
+
+func (listener testFailureListener) SubscribeEvents() []ApplicationEventId {
+	return []ApplicationEventId{
+		ApplicationFailedEventId(),
+	}
+}
+
+func (listener testFailureListener) OnApplicationEvent(context Context, event ApplicationEvent) {
+	*listener.events = append(*listener.events, event)
+}
+
+func newTestRunnerContext(runnerName string, runnerType goo.Type) *BaseApplicationContext {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	arguments, _ := NewApplicationArguments([]string{"migrate", "--dry-run"})
+	baseApplicationContext.SetApplicationArguments(arguments)
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition(runnerName, peas.NewSimplePeaDefinition(runnerType))
+	return baseApplicationContext
+}
+
+func TestBaseApplicationContext_CallRunners(t *testing.T) {
+	baseApplicationContext := newTestRunnerContext("testApplicationRunner", goo.GetType(newTestApplicationRunner))
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("testCommandLineRunner", peas.NewSimplePeaDefinition(goo.GetType(newTestCommandLineRunner)))
+
+	testInvokedRunners = make([]string, 0)
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.Equal(t, []string{"command-line:migrate", "application:migrate"}, testInvokedRunners)
+
+	arguments, err := baseApplicationContext.GetApplicationArguments()
+	assert.Nil(t, err)
+	sharedArguments := baseApplicationContext.GetSharedPea(applicationArguments)
+	assert.Equal(t, arguments, sharedArguments)
+}
+
+func TestBaseApplicationContext_CallRunnersWithFailure(t *testing.T) {
+	baseApplicationContext := newTestRunnerContext("testFailingRunner", goo.GetType(newTestFailingRunner))
+	events := make([]ApplicationEvent, 0)
+	baseApplicationContext.AddApplicationListener(testFailureListener{&events})
+
+	err := baseApplicationContext.Configure()
+	assert.NotNil(t, err)
+	assert.Equal(t, "test-runner-failure", err.Error())
+	assert.Equal(t, 1, len(events))
+	failedEvent, ok := events[0].(ApplicationFailedEvent)
+	assert.True(t, ok)
+	assert.Equal(t, err, failedEvent.GetError())
+}
+
+type testContextUsingRunner struct {
+	context ApplicationContext
+}
+
+func newTestContextUsingRunner() *testContextUsingRunner {
+	return &testContextUsingRunner{}
+}
+
+func (runner *testContextUsingRunner) SetApplicationContext(context ApplicationContext) {
+	runner.context = context
+}
+
+func (runner *testContextUsingRunner) Run(args ApplicationArguments) error {
+	baseApplicationContext := runner.context.(*BaseApplicationContext)
+	baseApplicationContext.AddFailureAnalyzer(FailureAnalyzerFunc(func(err error) *FailureAnalysis {
+		return nil
+	}))
+	return baseApplicationContext.Close()
+}
+
+func TestBaseApplicationContext_CallRunnersWithoutHoldingTheLock(t *testing.T) {
+	baseApplicationContext := newTestRunnerContext("testContextUsingRunner", goo.GetType(newTestContextUsingRunner))
+
+	done := make(chan error)
+	go func() {
+		done <- baseApplicationContext.Configure()
+	}()
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "runner could not use the context while it was being configured")
+	}
+	assert.True(t, baseApplicationContext.closed)
+}
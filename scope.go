@@ -0,0 +1,135 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	peas "github.com/procyon-projects/procyon-peas"
+	"sync"
+)
+
+const RequestScope peas.PeaScope = "request"
+
+type Scope interface {
+	Get(peaName string, objFunc peas.GetObjectFunc) (interface{}, error)
+	Remove(peaName string) interface{}
+	RegisterDestructionCallback(peaName string, callback func())
+}
+
+type SimpleScope struct {
+	instances            map[string]interface{}
+	destructionCallbacks map[string][]func()
+	mu                   sync.RWMutex
+}
+
+func NewSimpleScope() *SimpleScope {
+	return &SimpleScope{
+		instances:            make(map[string]interface{}, 0),
+		destructionCallbacks: make(map[string][]func(), 0),
+		mu:                   sync.RWMutex{},
+	}
+}
+
+func (scope *SimpleScope) Get(peaName string, objFunc peas.GetObjectFunc) (interface{}, error) {
+	if objFunc == nil {
+		return nil, errors.New("object function must not be null")
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if instance, ok := scope.instances[peaName]; ok {
+		return instance, nil
+	}
+	instance, err := objFunc()
+	if err != nil {
+		return nil, err
+	}
+	scope.instances[peaName] = instance
+	return instance, nil
+}
+
+func (scope *SimpleScope) Remove(peaName string) interface{} {
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	instance, ok := scope.instances[peaName]
+	if !ok {
+		return nil
+	}
+	delete(scope.instances, peaName)
+	delete(scope.destructionCallbacks, peaName)
+	return instance
+}
+
+func (scope *SimpleScope) RegisterDestructionCallback(peaName string, callback func()) {
+	if callback == nil {
+		panic("Destruction callback must not be null")
+	}
+	scope.mu.Lock()
+	scope.destructionCallbacks[peaName] = append(scope.destructionCallbacks[peaName], callback)
+	scope.mu.Unlock()
+}
+
+func (scope *SimpleScope) Destroy() {
+	scope.mu.Lock()
+	callbacks := scope.destructionCallbacks
+	scope.instances = make(map[string]interface{}, 0)
+	scope.destructionCallbacks = make(map[string][]func(), 0)
+	scope.mu.Unlock()
+	for _, peaCallbacks := range callbacks {
+		for _, callback := range peaCallbacks {
+			callback()
+		}
+	}
+}
+
+type RequestContext struct {
+	parent    *BaseApplicationContext
+	contextId ContextId
+	scope     *SimpleScope
+	bag       map[string]interface{}
+	mu        sync.RWMutex
+}
+
+func newRequestContext(parent *BaseApplicationContext, contextId ContextId) *RequestContext {
+	return &RequestContext{
+		parent:    parent,
+		contextId: contextId,
+		scope:     NewSimpleScope(),
+		bag:       make(map[string]interface{}, 0),
+		mu:        sync.RWMutex{},
+	}
+}
+
+func (ctx *RequestContext) GetContextId() ContextId {
+	return ctx.contextId
+}
+
+func (ctx *RequestContext) Get(key string) interface{} {
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	return ctx.bag[key]
+}
+
+func (ctx *RequestContext) Put(key string, value interface{}) {
+	ctx.mu.Lock()
+	ctx.bag[key] = value
+	ctx.mu.Unlock()
+}
+
+func (ctx *RequestContext) GetParent() ApplicationContext {
+	return ctx.parent
+}
+
+func (ctx *RequestContext) GetScope() Scope {
+	return ctx.scope
+}
+
+func (ctx *RequestContext) GetPea(name string) (interface{}, error) {
+	return ctx.parent.getPea(name, nil, ctx.scope)
+}
+
+func (ctx *RequestContext) GetPeaByNameAndType(name string, typ goo.Type) (interface{}, error) {
+	return ctx.parent.getPea(name, typ, ctx.scope)
+}
+
+func (ctx *RequestContext) Close() {
+	ctx.scope.Destroy()
+}
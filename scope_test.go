@@ -0,0 +1,162 @@
+package context
+
+import (
+	"errors"
+	"github.com/procyon-projects/goo"
+	core "github.com/procyon-projects/procyon-core"
+	peas "github.com/procyon-projects/procyon-peas"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testScopedComponent struct {
+	id int
+}
+
+func (component testScopedComponent) GetPeaScope() peas.PeaScope {
+	return RequestScope
+}
+
+var testScopedComponentCount = 0
+
+func newTestScopedComponent() *testScopedComponent {
+	testScopedComponentCount++
+	return &testScopedComponent{testScopedComponentCount}
+}
+
+func TestSimpleScope(t *testing.T) {
+	scope := NewSimpleScope()
+	_, err := scope.Get("test", nil)
+	assert.NotNil(t, err)
+
+	_, err = scope.Get("test", func() (interface{}, error) {
+		return nil, errors.New("test error")
+	})
+	assert.NotNil(t, err)
+
+	first, err := scope.Get("test", func() (interface{}, error) {
+		return newTestScopedComponent(), nil
+	})
+	assert.Nil(t, err)
+	second, err := scope.Get("test", func() (interface{}, error) {
+		return newTestScopedComponent(), nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, first == second)
+
+	destroyed := 0
+	scope.RegisterDestructionCallback("test", func() {
+		destroyed++
+	})
+	assert.Panics(t, func() {
+		scope.RegisterDestructionCallback("test", nil)
+	})
+	scope.Destroy()
+	assert.Equal(t, 1, destroyed)
+
+	third, _ := scope.Get("test", func() (interface{}, error) {
+		return newTestScopedComponent(), nil
+	})
+	assert.False(t, first == third)
+	assert.Equal(t, third, scope.Remove("test"))
+	assert.Nil(t, scope.Remove("test"))
+}
+
+func TestBaseApplicationContext_RegisterScope(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	assert.Panics(t, func() {
+		baseApplicationContext.RegisterScope("", NewSimpleScope())
+	})
+	assert.Panics(t, func() {
+		baseApplicationContext.RegisterScope("custom", nil)
+	})
+	assert.Panics(t, func() {
+		baseApplicationContext.RegisterScope(RequestScope, NewSimpleScope())
+	})
+	scope := NewSimpleScope()
+	baseApplicationContext.RegisterScope("custom", scope)
+	assert.Equal(t, scope, baseApplicationContext.GetRegisteredScope("custom"))
+}
+
+func TestBaseApplicationContext_GetPeaWithCustomScope(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("customScopedPea", NewScannedPeaDefinition("customScopedPea", goo.GetType(newTestScopedComponent), WithComponentScope("custom")))
+
+	_, err := baseApplicationContext.GetPea("customScopedPea")
+	assert.NotNil(t, err)
+
+	scope := NewSimpleScope()
+	baseApplicationContext.RegisterScope("custom", scope)
+	first, err := baseApplicationContext.GetPea("customScopedPea")
+	assert.Nil(t, err)
+	second, err := baseApplicationContext.GetPeaByNameAndType("customScopedPea", goo.GetType((*testScopedComponent)(nil)))
+	assert.Nil(t, err)
+	assert.True(t, first == second)
+
+	scope.Remove("customScopedPea")
+	third, err := baseApplicationContext.GetPea("customScopedPea")
+	assert.Nil(t, err)
+	assert.False(t, first == third)
+}
+
+func TestRequestContext(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("requestScopedPea", NewScannedPeaDefinition("requestScopedPea", goo.GetType(newTestScopedComponent), WithComponentScope(RequestScope)))
+
+	_, err := baseApplicationContext.GetPea("requestScopedPea")
+	assert.NotNil(t, err)
+
+	firstRequest := baseApplicationContext.NewRequestContext("first-request")
+	secondRequest := baseApplicationContext.NewRequestContext("second-request")
+	assert.Equal(t, ContextId("first-request"), firstRequest.GetContextId())
+	assert.Equal(t, baseApplicationContext, firstRequest.GetParent())
+
+	firstRequest.Put("test-key", "test-value")
+	assert.Equal(t, "test-value", firstRequest.Get("test-key"))
+	assert.Nil(t, secondRequest.Get("test-key"))
+
+	first, err := firstRequest.GetPea("requestScopedPea")
+	assert.Nil(t, err)
+	again, err := firstRequest.GetPeaByNameAndType("requestScopedPea", goo.GetType((*testScopedComponent)(nil)))
+	assert.Nil(t, err)
+	assert.True(t, first == again)
+
+	second, err := secondRequest.GetPea("requestScopedPea")
+	assert.Nil(t, err)
+	assert.False(t, first == second)
+
+	destroyed := false
+	firstRequest.GetScope().RegisterDestructionCallback("requestScopedPea", func() {
+		destroyed = true
+	})
+	firstRequest.Close()
+	assert.True(t, destroyed)
+}
+
+type testRequestConsumer struct {
+	component *testScopedComponent
+}
+
+func newTestRequestConsumer(component *testScopedComponent) *testRequestConsumer {
+	return &testRequestConsumer{component}
+}
+
+func TestBaseApplicationContext_SharedPeaDependingOnRequestScopedPea(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	registry := baseApplicationContext.GetPeaFactory().(peas.PeaDefinitionRegistry)
+	registry.RegisterPeaDefinition("requestScopedPea", NewScannedPeaDefinition("requestScopedPea", goo.GetType(newTestScopedComponent), WithComponentScope(RequestScope)))
+	registry.RegisterPeaDefinition("sharedConsumer", NewScannedPeaDefinition("sharedConsumer", goo.GetType(newTestRequestConsumer), WithLazyInit(true)))
+
+	_, err := baseApplicationContext.NewRequestContext("request").GetPea("sharedConsumer")
+	assert.NotNil(t, err)
+	assert.Equal(t, "shared pea 'sharedConsumer' cannot depend on the request scoped pea 'requestScopedPea'", err.Error())
+	assert.False(t, baseApplicationContext.ContainsSharedPea("sharedConsumer"))
+
+	registry.RemovePeaDefinition("sharedConsumer")
+	registry.RegisterPeaDefinition("sharedConsumer", NewScannedPeaDefinition("sharedConsumer", goo.GetType(newTestRequestConsumer)))
+	assert.NotNil(t, baseApplicationContext.Configure())
+}
@@ -0,0 +1,191 @@
+package context
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type StartupStep struct {
+	id        int
+	parentId  int
+	name      string
+	tags      map[string]string
+	startTime time.Time
+	duration  time.Duration
+	ended     bool
+	recorder  *StartupRecorder
+}
+
+func (step *StartupStep) GetId() int {
+	return step.id
+}
+
+func (step *StartupStep) GetParentId() int {
+	return step.parentId
+}
+
+func (step *StartupStep) GetName() string {
+	return step.name
+}
+
+func (step *StartupStep) GetTags() map[string]string {
+	return step.tags
+}
+
+func (step *StartupStep) GetStartTime() time.Time {
+	return step.startTime
+}
+
+func (step *StartupStep) GetDuration() time.Duration {
+	return step.duration
+}
+
+func (step *StartupStep) IsEnded() bool {
+	return step.ended
+}
+
+func (step *StartupStep) Tag(key string, value string) *StartupStep {
+	if step.recorder == nil {
+		return step
+	}
+	step.recorder.mu.Lock()
+	step.tags[key] = value
+	step.recorder.mu.Unlock()
+	return step
+}
+
+func (step *StartupStep) End() {
+	if step.recorder == nil {
+		return
+	}
+	step.recorder.endStep(step)
+}
+
+type StartupRecorder struct {
+	steps  []*StartupStep
+	active []*StartupStep
+	mu     sync.Mutex
+}
+
+func NewStartupRecorder() *StartupRecorder {
+	return &StartupRecorder{
+		steps:  make([]*StartupStep, 0),
+		active: make([]*StartupStep, 0),
+		mu:     sync.Mutex{},
+	}
+}
+
+func (recorder *StartupRecorder) Start(name string) *StartupStep {
+	if name == "" {
+		panic("Step name must not be empty")
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	parentId := 0
+	if len(recorder.active) != 0 {
+		parentId = recorder.active[len(recorder.active)-1].id
+	}
+	step := &StartupStep{
+		id:        len(recorder.steps) + 1,
+		parentId:  parentId,
+		name:      name,
+		tags:      make(map[string]string, 0),
+		startTime: time.Now(),
+		recorder:  recorder,
+	}
+	recorder.steps = append(recorder.steps, step)
+	recorder.active = append(recorder.active, step)
+	return step
+}
+
+func (recorder *StartupRecorder) endStep(step *StartupStep) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if step.ended {
+		return
+	}
+	step.duration = time.Since(step.startTime)
+	step.ended = true
+	for index := len(recorder.active) - 1; index >= 0; index-- {
+		if recorder.active[index] == step {
+			recorder.active = append(recorder.active[:index], recorder.active[index+1:]...)
+			break
+		}
+	}
+}
+
+func (recorder *StartupRecorder) GetSteps() []StartupStep {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	steps := make([]StartupStep, 0)
+	for _, step := range recorder.steps {
+		copiedStep := *step
+		copiedStep.tags = make(map[string]string, 0)
+		for key, value := range step.tags {
+			copiedStep.tags[key] = value
+		}
+		copiedStep.recorder = nil
+		steps = append(steps, copiedStep)
+	}
+	return steps
+}
+
+func (recorder *StartupRecorder) GetSlowestSteps(count int) []StartupStep {
+	steps := make([]StartupStep, 0)
+	for _, step := range recorder.GetSteps() {
+		if step.ended {
+			steps = append(steps, step)
+		}
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].duration > steps[j].duration
+	})
+	if count >= 0 && count < len(steps) {
+		return steps[:count]
+	}
+	return steps
+}
+
+func (recorder *StartupRecorder) ToJSON() ([]byte, error) {
+	type stepData struct {
+		Id        int               `json:"id"`
+		ParentId  int               `json:"parentId,omitempty"`
+		Name      string            `json:"name"`
+		Tags      map[string]string `json:"tags,omitempty"`
+		StartTime time.Time         `json:"startTime"`
+		Duration  int64             `json:"durationNanos"`
+	}
+	data := make([]stepData, 0)
+	for _, step := range recorder.GetSteps() {
+		data = append(data, stepData{
+			step.id,
+			step.parentId,
+			step.name,
+			step.tags,
+			step.startTime,
+			step.duration.Nanoseconds(),
+		})
+	}
+	return json.MarshalIndent(data, "", "  ")
+}
+
+func (recorder *StartupRecorder) Summary(count int) string {
+	var builder strings.Builder
+	builder.WriteString("Slowest startup steps :")
+	for _, step := range recorder.GetSlowestSteps(count) {
+		builder.WriteString(fmt.Sprintf("\n\t%-12s %s", step.duration.String(), step.name))
+		if len(step.tags) != 0 {
+			tags := make([]string, 0)
+			for key, value := range step.tags {
+				tags = append(tags, key+"="+value)
+			}
+			sort.Strings(tags)
+			builder.WriteString(" [" + strings.Join(tags, ", ") + "]")
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,73 @@
+package context
+
+import (
+	"encoding/json"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartupRecorder(t *testing.T) {
+	recorder := NewStartupRecorder()
+	assert.Panics(t, func() {
+		recorder.Start("")
+	})
+
+	parentStep := recorder.Start("parent").Tag("key", "value")
+	childStep := recorder.Start("child")
+	time.Sleep(2 * time.Millisecond)
+	childStep.End()
+	parentStep.End()
+	parentStep.End()
+	recorder.Start("not-ended")
+
+	steps := recorder.GetSteps()
+	assert.Equal(t, 3, len(steps))
+	assert.Equal(t, "parent", steps[0].GetName())
+	assert.Equal(t, "value", steps[0].GetTags()["key"])
+	assert.Equal(t, 0, steps[0].GetParentId())
+	assert.Equal(t, steps[0].GetId(), steps[1].GetParentId())
+	assert.Equal(t, 0, steps[2].GetParentId())
+	assert.True(t, steps[0].IsEnded())
+	assert.False(t, steps[2].IsEnded())
+	assert.True(t, steps[0].GetDuration() >= steps[1].GetDuration())
+
+	slowestSteps := recorder.GetSlowestSteps(1)
+	assert.Equal(t, 1, len(slowestSteps))
+	assert.Equal(t, "parent", slowestSteps[0].GetName())
+	assert.Equal(t, 2, len(recorder.GetSlowestSteps(-1)))
+
+	summary := recorder.Summary(5)
+	assert.True(t, strings.Contains(summary, "parent [key=value]"))
+	assert.False(t, strings.Contains(summary, "not-ended"))
+
+	data, err := recorder.ToJSON()
+	assert.Nil(t, err)
+	result := make([]map[string]interface{}, 0)
+	assert.Nil(t, json.Unmarshal(data, &result))
+	assert.Equal(t, 3, len(result))
+	assert.Equal(t, "child", result[1]["name"])
+}
+
+func TestBaseApplicationContext_StartupSteps(t *testing.T) {
+	baseApplicationContext := NewBaseApplicationContext("app-id", "context-id", testConfigurableContextAdapter{})
+	baseApplicationContext.SetLogger(NewSimpleLogger())
+	baseApplicationContext.SetEnvironment(core.NewStandardEnvironment())
+	assert.Equal(t, int64(0), baseApplicationContext.GetStartupTimestamp())
+	assert.Nil(t, baseApplicationContext.Configure())
+	assert.NotEqual(t, int64(0), baseApplicationContext.GetStartupTimestamp())
+
+	stepNames := make(map[string]bool, 0)
+	for _, step := range baseApplicationContext.GetStartupRecorder().GetSteps() {
+		assert.True(t, step.IsEnded())
+		stepNames[step.GetName()] = true
+	}
+	assert.True(t, stepNames["context.configure"])
+	assert.True(t, stepNames["context.pea-factory.prepare"])
+	assert.True(t, stepNames["context.pea-definition-registry.process"])
+	assert.True(t, stepNames["context.pea-factory.process"])
+	assert.True(t, stepNames["context.pea.instantiate"])
+	assert.True(t, stepNames["context.finish-configure"])
+}
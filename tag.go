@@ -0,0 +1,47 @@
+package context
+
+import "strings"
+
+type PropertyTag struct {
+	name    string
+	options []string
+}
+
+func NewPropertyTag(name string, options ...string) PropertyTag {
+	return PropertyTag{
+		name,
+		options,
+	}
+}
+
+func ParsePropertyTag(value string) PropertyTag {
+	parts := strings.Split(value, ",")
+	tag := PropertyTag{
+		name:    strings.TrimSpace(parts[0]),
+		options: make([]string, 0),
+	}
+	for _, option := range parts[1:] {
+		option = strings.TrimSpace(option)
+		if option != "" {
+			tag.options = append(tag.options, option)
+		}
+	}
+	return tag
+}
+
+func (tag PropertyTag) GetName() string {
+	return tag.name
+}
+
+func (tag PropertyTag) GetOptions() []string {
+	return tag.options
+}
+
+func (tag PropertyTag) HasOption(option string) bool {
+	for _, tagOption := range tag.options {
+		if tagOption == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package context
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParsePropertyTag(t *testing.T) {
+	tag := ParsePropertyTag("name, omitempty,string")
+	assert.Equal(t, "name", tag.GetName())
+	assert.Equal(t, []string{"omitempty", "string"}, tag.GetOptions())
+	assert.True(t, tag.HasOption("omitempty"))
+	assert.True(t, tag.HasOption("string"))
+	assert.False(t, tag.HasOption("inline"))
+
+	tag = ParsePropertyTag(",omitempty")
+	assert.Equal(t, "", tag.GetName())
+	assert.Equal(t, []string{"omitempty"}, tag.GetOptions())
+
+	tag = ParsePropertyTag("-")
+	assert.Equal(t, "-", tag.GetName())
+	assert.Equal(t, 0, len(tag.GetOptions()))
+}
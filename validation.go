@@ -0,0 +1,256 @@
+package context
+
+import (
+	"errors"
+	"fmt"
+	"github.com/procyon-projects/goo"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type PropertyValidationFailure struct {
+	propertyName string
+	value        interface{}
+	constraint   string
+	cause        error
+}
+
+func NewPropertyValidationFailure(propertyName string, value interface{}, constraint string, cause error) PropertyValidationFailure {
+	return PropertyValidationFailure{
+		propertyName,
+		value,
+		constraint,
+		cause,
+	}
+}
+
+func (failure PropertyValidationFailure) GetPropertyName() string {
+	return failure.propertyName
+}
+
+func (failure PropertyValidationFailure) GetValue() interface{} {
+	return failure.value
+}
+
+func (failure PropertyValidationFailure) GetConstraint() string {
+	return failure.constraint
+}
+
+func (failure PropertyValidationFailure) GetCause() error {
+	return failure.cause
+}
+
+func (failure PropertyValidationFailure) Unwrap() error {
+	return failure.cause
+}
+
+func (failure PropertyValidationFailure) Error() string {
+	return "property '" + failure.propertyName + "' is not valid : " + failure.cause.Error()
+}
+
+type ValidationError struct {
+	prefix   string
+	failures []PropertyValidationFailure
+}
+
+func NewValidationError(prefix string, failures []PropertyValidationFailure) ValidationError {
+	return ValidationError{
+		prefix,
+		failures,
+	}
+}
+
+func (err ValidationError) GetPrefix() string {
+	return err.prefix
+}
+
+func (err ValidationError) GetFailures() []PropertyValidationFailure {
+	return err.failures
+}
+
+func (err ValidationError) Error() string {
+	messages := make([]string, 0)
+	for _, failure := range err.failures {
+		messages = append(messages, failure.Error())
+	}
+	return "properties with the prefix '" + err.prefix + "' are not valid : " + strings.Join(messages, "; ")
+}
+
+func (binder ConfigurationPropertiesBinder) validate(prefix string, target interface{}) error {
+	failures := binder.validateValue(prefix, reflect.ValueOf(target), make([]PropertyValidationFailure, 0))
+	if len(failures) != 0 {
+		return NewValidationError(prefix, failures)
+	}
+	return nil
+}
+
+func (binder ConfigurationPropertiesBinder) validateValue(propertyName string, value reflect.Value, failures []PropertyValidationFailure) []PropertyValidationFailure {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return failures
+		}
+		value = value.Elem()
+	}
+	if isValueType(value.Type()) {
+		return failures
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		failures = binder.validateStruct(propertyName, value, failures)
+	case reflect.Slice, reflect.Array:
+		if binder.isLeafType(value.Type().Elem()) {
+			return failures
+		}
+		for index := 0; index < value.Len(); index++ {
+			failures = binder.validateValue(propertyName+"["+strconv.Itoa(index)+"]", value.Index(index), failures)
+		}
+	case reflect.Map:
+		if binder.isLeafType(value.Type().Elem()) {
+			return failures
+		}
+		for _, key := range value.MapKeys() {
+			failures = binder.validateValue(propertyName+"."+fmt.Sprint(key.Interface()), value.MapIndex(key), failures)
+		}
+	}
+	return failures
+}
+
+func (binder ConfigurationPropertiesBinder) validateStruct(propertyName string, value reflect.Value, failures []PropertyValidationFailure) []PropertyValidationFailure {
+	structValue := reflect.New(value.Type())
+	structValue.Elem().Set(value)
+	for _, field := range goo.GetType(structValue.Interface()).ToStructType().GetExportedFields() {
+		tag, ok := binder.getPropertyTag(field)
+		if !ok {
+			continue
+		}
+		fieldName := binder.getFullPropertyName(propertyName, tag.GetName())
+		fieldValue := value.FieldByName(field.GetName())
+		failures = binder.validateField(fieldName, field, fieldValue, failures)
+		failures = binder.validateValue(fieldName, fieldValue, failures)
+	}
+	/* custom validation is invoked after the fields are validated */
+	if validatable, ok := structValue.Interface().(ValidatableProperties); ok {
+		if err := validatable.Validate(); err != nil {
+			failures = append(failures, NewPropertyValidationFailure(propertyName, nil, "Validate", err))
+		}
+	}
+	return failures
+}
+
+func (binder ConfigurationPropertiesBinder) validateField(propertyName string, field goo.Field, value reflect.Value, failures []PropertyValidationFailure) []PropertyValidationFailure {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if binder.isRequiredField(field) {
+				failures = append(failures, NewPropertyValidationFailure(propertyName, nil, "required", errors.New("must not be empty")))
+			}
+			return failures
+		}
+		value = value.Elem()
+	}
+	if binder.isRequiredField(field) && binder.isEmptyValue(value) {
+		failures = append(failures, NewPropertyValidationFailure(propertyName, value.Interface(), "required", errors.New("must not be empty")))
+	}
+	for _, constraint := range []string{"min", "max", "oneof", "regex"} {
+		tag, err := field.GetTagByName(constraint)
+		if err != nil {
+			continue
+		}
+		if err = binder.checkConstraint(constraint, strings.TrimSpace(tag.Value), strings.TrimSpace(binder.getTagValue(field, "unit")), value); err != nil {
+			failures = append(failures, NewPropertyValidationFailure(propertyName, value.Interface(), constraint, err))
+		}
+	}
+	return failures
+}
+
+func (binder ConfigurationPropertiesBinder) isRequiredField(field goo.Field) bool {
+	requiredTag, err := field.GetTagByName("required")
+	if err != nil {
+		return false
+	}
+	required, err := strconv.ParseBool(strings.TrimSpace(requiredTag.Value))
+	return err == nil && required
+}
+
+func (binder ConfigurationPropertiesBinder) isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array, reflect.String:
+		return value.Len() == 0
+	}
+	return reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface())
+}
+
+func (binder ConfigurationPropertiesBinder) checkConstraint(constraint string, constraintValue string, unit string, value reflect.Value) error {
+	switch constraint {
+	case "min", "max":
+		return binder.checkRange(constraint == "min", constraintValue, unit, value)
+	case "oneof":
+		actualValue := fmt.Sprint(value.Interface())
+		allowedValues := strings.Fields(constraintValue)
+		for _, allowedValue := range allowedValues {
+			if allowedValue == actualValue {
+				return nil
+			}
+		}
+		return errors.New("must be one of [" + strings.Join(allowedValues, ", ") + "]")
+	case "regex":
+		if value.Kind() != reflect.String {
+			return errors.New("regex constraint is only supported for strings")
+		}
+		expression, err := regexp.Compile(constraintValue)
+		if err != nil {
+			return errors.New("invalid regex constraint '" + constraintValue + "'")
+		}
+		if !expression.MatchString(value.String()) {
+			return errors.New("must match the pattern '" + constraintValue + "'")
+		}
+	}
+	return nil
+}
+
+func (binder ConfigurationPropertiesBinder) checkRange(isMin bool, constraintValue string, unit string, value reflect.Value) error {
+	var actual, limit float64
+	var err error
+	/* the bounds are parsed in the same way as the bound values, including the unit of the field */
+	switch {
+	case value.Type() == durationType:
+		var duration time.Duration
+		duration, err = parseDuration(constraintValue, unit)
+		actual, limit = float64(value.Int()), float64(duration)
+	case value.Type() == dataSizeType ||
+		(isIntegerKind(value.Kind()) && (isDataSizeUnit(unit) || (unit == "" && hasDataSizeUnit(constraintValue)))):
+		var size DataSize
+		size, err = ParseDataSize(constraintValue, unit)
+		if value.Kind() >= reflect.Uint && value.Kind() <= reflect.Uintptr {
+			actual, limit = float64(value.Uint()), float64(size)
+		} else {
+			actual, limit = float64(value.Int()), float64(size)
+		}
+	case value.Kind() >= reflect.Int && value.Kind() <= reflect.Int64:
+		actual = float64(value.Int())
+		limit, err = strconv.ParseFloat(constraintValue, 64)
+	case value.Kind() >= reflect.Uint && value.Kind() <= reflect.Uintptr:
+		actual = float64(value.Uint())
+		limit, err = strconv.ParseFloat(constraintValue, 64)
+	case value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64:
+		actual = value.Float()
+		limit, err = strconv.ParseFloat(constraintValue, 64)
+	case value.Kind() == reflect.String || value.Kind() == reflect.Slice || value.Kind() == reflect.Map || value.Kind() == reflect.Array:
+		/* the length is checked for strings and collections */
+		actual = float64(value.Len())
+		limit, err = strconv.ParseFloat(constraintValue, 64)
+	default:
+		return errors.New("range constraint is not supported for the type " + value.Type().String())
+	}
+	if err != nil {
+		return errors.New("invalid range constraint '" + constraintValue + "'")
+	}
+	if isMin && actual < limit {
+		return errors.New("must be greater than or equal to " + constraintValue)
+	} else if !isMin && actual > limit {
+		return errors.New("must be less than or equal to " + constraintValue)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package context
+
+import (
+	"errors"
+	core "github.com/procyon-projects/procyon-core"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testValidatedServerProperties struct {
+	Host string `json:"host" required:"true"`
+	Port int    `json:"port" min:"1" max:"65535"`
+}
+
+type testValidatedProperties struct {
+	Name     string                          `json:"name" min:"3" max:"8" regex:"^[a-z]+$"`
+	Level    string                          `json:"level" oneof:"debug info warn"`
+	Ratio    float64                         `json:"ratio" max:"1"`
+	Timeout  time.Duration                   `json:"timeout" min:"1s" max:"1m"`
+	Tags     []string                        `json:"tags" required:"true"`
+	Servers  []testValidatedServerProperties `json:"servers"`
+	Fallback *testValidatedServerProperties  `json:"fallback"`
+	Clusters map[string]testValidatedServerProperties
+}
+
+func (testValidatedProperties) GetConfigurationPrefix() string {
+	return "app"
+}
+
+func (properties testValidatedProperties) Validate() error {
+	if properties.Level == "debug" && properties.Ratio > 0.5 {
+		return errors.New("ratio must not exceed 0.5 in debug level")
+	}
+	return nil
+}
+
+func TestConfigurationPropertiesBinder_Validate(t *testing.T) {
+	binder := newConfigurationPropertiesBinder(core.NewStandardEnvironment(), core.NewDefaultTypeConverterService())
+
+	properties := &testValidatedProperties{
+		Name:    "app",
+		Level:   "info",
+		Ratio:   0.5,
+		Timeout: 30 * time.Second,
+		Tags:    []string{"test"},
+		Servers: []testValidatedServerProperties{{"localhost", 8080}},
+	}
+	assert.Nil(t, binder.validate("app", properties))
+
+	properties = &testValidatedProperties{
+		Name:     "Application",
+		Level:    "debug",
+		Ratio:    1.5,
+		Timeout:  2 * time.Minute,
+		Servers:  []testValidatedServerProperties{{"localhost", 8080}, {"", 0}},
+		Fallback: &testValidatedServerProperties{"fallback", 70000},
+	}
+	err := binder.validate("app", properties)
+	assert.NotNil(t, err)
+	validationError, ok := err.(ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, "app", validationError.GetPrefix())
+
+	failures := validationError.GetFailures()
+	propertyNames := make([]string, 0)
+	constraints := make([]string, 0)
+	for _, failure := range failures {
+		propertyNames = append(propertyNames, failure.GetPropertyName())
+		constraints = append(constraints, failure.GetConstraint())
+	}
+	assert.Equal(t, []string{"app.name", "app.name", "app.ratio", "app.timeout", "app.tags",
+		"app.servers[1].host", "app.servers[1].port", "app.fallback.port", "app"}, propertyNames)
+	assert.Equal(t, []string{"max", "regex", "max", "max", "required", "required", "min", "max", "Validate"}, constraints)
+	assert.Equal(t, "Application", failures[0].GetValue())
+	assert.True(t, strings.Contains(err.Error(), "must match the pattern '^[a-z]+$'"))
+	assert.True(t, strings.Contains(err.Error(), "ratio must not exceed 0.5 in debug level"))
+}
+
+func TestConfigurationPropertiesBinder_ValidateWithInvalidConstraint(t *testing.T) {
+	type invalidConstraintProperties struct {
+		Port    int    `json:"port" min:"one"`
+		Enabled bool   `json:"enabled" max:"1"`
+		Name    string `json:"name" regex:"["`
+	}
+	binder := newConfigurationPropertiesBinder(core.NewStandardEnvironment(), core.NewDefaultTypeConverterService())
+
+	err := binder.validate("app", &invalidConstraintProperties{})
+	assert.NotNil(t, err)
+	failures := err.(ValidationError).GetFailures()
+	assert.Equal(t, 3, len(failures))
+	assert.Equal(t, "invalid range constraint 'one'", failures[0].GetCause().Error())
+	assert.Equal(t, "range constraint is not supported for the type bool", failures[1].GetCause().Error())
+	assert.Equal(t, "invalid regex constraint '['", failures[2].GetCause().Error())
+}
+
+func TestConfigurationPropertiesBinder_BindWithValidation(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--app.name=test", "--app.level=trace", "--app.tags=a", "--app.timeout=5000000000"}))
+	binder := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+
+	err := binder.Bind(&testValidatedProperties{})
+	assert.NotNil(t, err)
+	validationError, ok := err.(ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(validationError.GetFailures()))
+	assert.Equal(t, "app.level", validationError.GetFailures()[0].GetPropertyName())
+	assert.Equal(t, "must be one of [debug, info, warn]", validationError.GetFailures()[0].GetCause().Error())
+
+	analysis := analyzeFailure(getDefaultFailureAnalyzers(), err)
+	assert.NotNil(t, analysis)
+	assert.True(t, strings.Contains(analysis.GetDescription(), "Property: app.level\n    Value: trace\n    Constraint: oneof"))
+}
+
+type testValidatedLimitProperties struct {
+	Retention time.Duration `json:"retention" max:"7d"`
+	Interval  time.Duration `json:"interval" unit:"s" min:"5" max:"60"`
+	MaxUpload int64         `json:"max-upload" min:"1KB" max:"10MB"`
+	Buffer    DataSize      `json:"buffer" unit:"KiB" max:"64"`
+	Port      int           `json:"port" required:"true"`
+}
+
+func (testValidatedLimitProperties) GetConfigurationPrefix() string {
+	return "limits"
+}
+
+func TestConfigurationPropertiesBinder_BindAndValidateWithUnits(t *testing.T) {
+	standardEnvironment := core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--limits.retention=7d", "--limits.interval=30", "--limits.max-upload=2MB", "--limits.buffer=32", "--limits.port=8080"}))
+	binder := newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+	properties := &testValidatedLimitProperties{}
+	assert.Nil(t, binder.Bind(properties))
+
+	standardEnvironment = core.NewStandardEnvironment()
+	standardEnvironment.GetPropertySources().Add(core.NewSimpleCommandLinePropertySource([]string{"app",
+		"--limits.retention=8d", "--limits.interval=90", "--limits.max-upload=20MB", "--limits.buffer=128", "--limits.port=0"}))
+	binder = newConfigurationPropertiesBinder(standardEnvironment, core.NewDefaultTypeConverterService())
+	err := binder.Bind(&testValidatedLimitProperties{})
+	assert.NotNil(t, err)
+	constraints := make([]string, 0)
+	for _, failure := range err.(ValidationError).GetFailures() {
+		constraints = append(constraints, failure.GetPropertyName()+":"+failure.GetConstraint())
+	}
+	/* a zero value fails the required constraint even if the property is specified */
+	assert.Equal(t, []string{"limits.retention:max", "limits.interval:max", "limits.max-upload:max", "limits.buffer:max", "limits.port:required"}, constraints)
+}